@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package checkpoint persists relay progress so a restarted relayer resumes where it stopped
+package checkpoint
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	//last fully processed block height of a direction
+	PREFIX_HEIGHT = byte(0x01)
+	//request id already relayed in a direction
+	PREFIX_RELAYED = byte(0x02)
+)
+
+//Store records, per direction (from chain -> to chain), the last fully processed block
+//and the request ids already relayed
+type Store struct {
+	db *leveldb.DB
+}
+
+//NewStore open or create the checkpoint store at path
+func NewStore(path string) (*Store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("leveldb.OpenFile %s error:%s", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+//GetHeight return the last fully processed height of direction fromChainID -> toChainID,
+//ok is false if the direction has never been checkpointed
+func (this *Store) GetHeight(fromChainID, toChainID uint64) (height uint32, ok bool, err error) {
+	value, err := this.db.Get(heightKey(fromChainID, toChainID), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("get height error:%s", err)
+	}
+	if len(value) != 4 {
+		return 0, false, fmt.Errorf("invalid height value length:%d", len(value))
+	}
+	return binary.LittleEndian.Uint32(value), true, nil
+}
+
+//PutHeight mark height as fully processed in direction fromChainID -> toChainID. Relayed request
+//ids recorded for that direction are no longer needed once their block is done, so they are
+//dropped in the same batch
+func (this *Store) PutHeight(fromChainID, toChainID uint64, height uint32) error {
+	batch := new(leveldb.Batch)
+	iter := this.db.NewIterator(util.BytesPrefix(directionKey(PREFIX_RELAYED, fromChainID, toChainID)), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return fmt.Errorf("iterate relayed requests error:%s", err)
+	}
+	value := make([]byte, 4)
+	binary.LittleEndian.PutUint32(value, height)
+	batch.Put(heightKey(fromChainID, toChainID), value)
+	if err := this.db.Write(batch, nil); err != nil {
+		return fmt.Errorf("write height error:%s", err)
+	}
+	return nil
+}
+
+//IsRelayed return whether requestID has already been relayed in direction fromChainID -> toChainID
+//since the last checkpointed height
+func (this *Store) IsRelayed(fromChainID, toChainID, requestID uint64) (bool, error) {
+	ok, err := this.db.Has(relayedKey(fromChainID, toChainID, requestID), nil)
+	if err != nil {
+		return false, fmt.Errorf("has relayed error:%s", err)
+	}
+	return ok, nil
+}
+
+//MarkRelayed record requestID as relayed in direction fromChainID -> toChainID
+func (this *Store) MarkRelayed(fromChainID, toChainID, requestID uint64) error {
+	err := this.db.Put(relayedKey(fromChainID, toChainID, requestID), []byte{1}, nil)
+	if err != nil {
+		return fmt.Errorf("put relayed error:%s", err)
+	}
+	return nil
+}
+
+//Close flush and close the underlying db
+func (this *Store) Close() error {
+	return this.db.Close()
+}
+
+func directionKey(prefix byte, fromChainID, toChainID uint64) []byte {
+	key := make([]byte, 17)
+	key[0] = prefix
+	binary.LittleEndian.PutUint64(key[1:], fromChainID)
+	binary.LittleEndian.PutUint64(key[9:], toChainID)
+	return key
+}
+
+func heightKey(fromChainID, toChainID uint64) []byte {
+	return directionKey(PREFIX_HEIGHT, fromChainID, toChainID)
+}
+
+func relayedKey(fromChainID, toChainID, requestID uint64) []byte {
+	key := directionKey(PREFIX_RELAYED, fromChainID, toChainID)
+	id := make([]byte, 8)
+	binary.LittleEndian.PutUint64(id, requestID)
+	return append(key, id...)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package checkpoint
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewStore(dir)
+	assert.Nil(t, err)
+
+	_, ok, err := store.GetHeight(0, 1)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, store.MarkRelayed(0, 1, 7))
+	relayed, err := store.IsRelayed(0, 1, 7)
+	assert.Nil(t, err)
+	assert.True(t, relayed)
+	relayed, err = store.IsRelayed(1, 0, 7)
+	assert.Nil(t, err)
+	assert.False(t, relayed)
+
+	assert.Nil(t, store.PutHeight(0, 1, 100))
+	assert.Nil(t, store.Close())
+
+	store, err = NewStore(dir)
+	assert.Nil(t, err)
+	defer store.Close()
+	height, ok, err := store.GetHeight(0, 1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(100), height)
+	_, ok, err = store.GetHeight(1, 0)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	relayed, err = store.IsRelayed(0, 1, 7)
+	assert.Nil(t, err)
+	assert.False(t, relayed)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/ontio/crossChainClient/config"
@@ -8,19 +9,91 @@ import (
 )
 
 var (
-	LogLevelFlag = cli.UintFlag{
-		Name:  "loglevel",
-		Usage: "Set the log level to `<level>` (0~6). 0:Trace 1:Debug 2:Info 3:Warn 4:Error 5:Fatal 6:MaxLevel",
-		Value: config.DEFAULT_LOG_LEVEL,
-	}
-
 	ConfigPathFlag = cli.StringFlag{
 		Name:  "cliconfig",
 		Usage: "Server config file `<path>`",
 		Value: config.DEFAULT_CONFIG_FILE_NAME,
 	}
+	ConfigFormatFlag = cli.StringFlag{
+		Name:  "config-format",
+		Usage: "Read the config file as `<format>`: json, yaml or toml, by default from the file extension",
+	}
+	SignerListenFlag = cli.StringFlag{
+		Name:  "listen",
+		Usage: "Serve the remote signer protocol on `<address>`",
+		Value: config.DEFAULT_SIGNER_ADDRESS,
+	}
+	SignerTokenFileFlag = cli.StringFlag{
+		Name:  "token-file",
+		Usage: "Read the API token the relayers must present for the accounts without their own from `<path>`",
+	}
+	SignerAccountFlag = cli.StringSliceFlag{
+		Name:  "account",
+		Usage: "Serve the wallet account `<address>`, or <address>=<path> to read its own API token from <path>. Repeat it for each account",
+	}
+	QueueDirFlag = cli.StringFlag{
+		Name:  "queue-dir",
+		Usage: "Sign the multi-signature txs waiting in the signing queue `<dir>`",
+	}
+	WatchFlag = cli.BoolFlag{
+		Name:  "watch",
+		Usage: "Keep signing the txs added to the queue until interrupted",
+	}
+	LiveFlag = cli.BoolFlag{
+		Name:  "live",
+		Usage: "Also check that the node of every chain is reachable and on the expected network",
+	}
+	FromChainFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Use `<chain>` as the source chain: main, side or a chain id",
+	}
+	ToChainFlag = cli.StringFlag{
+		Name:  "to",
+		Usage: "Use `<chain>` as the destination chain: main, side or a chain id, by default the side chain from the main chain and the main chain otherwise",
+	}
+	HeightFlag = cli.UintFlag{
+		Name:  "height",
+		Usage: "Relay the requests created by the block at `<height>` of the source chain",
+	}
+	RequestIDFlag = cli.Uint64Flag{
+		Name:  "request-id",
+		Usage: "Relay only the request `<id>`, by default every request of the block",
+	}
+	FromHeightFlag = cli.UintFlag{
+		Name:  "from-height",
+		Usage: "Sync the headers of the source chain from `<height>`",
+	}
+	ToHeightFlag = cli.UintFlag{
+		Name:  "to-height",
+		Usage: "Sync the headers of the source chain up to `<height>` included, by default only the one at --from-height",
+	}
+	KeyOnlyFlag = cli.BoolFlag{
+		Name:  "key-only",
+		Usage: "Sync only the key headers, which change the consensus config",
+	}
+	JsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the status as json",
+	}
+	StatusBlocksFlag = cli.UintFlag{
+		Name:  "blocks",
+		Usage: "Scan the last `<count>` blocks of the source chains for pending requests",
+		Value: config.DEFAULT_STATUS_BLOCKS,
+	}
 )
 
+//ConfigFlags return a flag overriding each config field, see config.ConfigField
+func ConfigFlags() []cli.Flag {
+	flags := make([]cli.Flag, 0)
+	for _, field := range config.ConfigFields() {
+		flags = append(flags, cli.StringFlag{
+			Name:  field.Flag,
+			Usage: fmt.Sprintf("Override the config %s with `<value>`, also set by $%s", field.Name, field.Env),
+		})
+	}
+	return flags
+}
+
 //GetFlagName deal with short flag, and return the flag name whether flag name have short name
 func GetFlagName(flag cli.Flag) string {
 	name := flag.GetName()
@@ -22,26 +22,138 @@ import (
 	"fmt"
 
 	sdk "github.com/ontio/ontology-go-sdk"
-	"github.com/ontio/ontology/common/password"
 )
 
-func GetAccountByPassword(sdk *sdk.OntologySdk, path string) (*sdk.Account, bool) {
-	wallet, err := sdk.OpenWallet(path)
+//AccountSelector tell which account of the wallet to use, the default account if no field is set
+type AccountSelector struct {
+	Address string //base58 address
+	Label   string
+	Index   int //index in the wallet, starting from 1
+}
+
+//SelectAccountData return the data of the account chosen by selector, it fail if several fields
+//are set or the account does not exist
+func SelectAccountData(wallet *sdk.Wallet, selector *AccountSelector) (*sdk.AccountData, error) {
+	set := 0
+	for _, ok := range []bool{selector.Address != "", selector.Label != "", selector.Index != 0} {
+		if ok {
+			set++
+		}
+	}
+	switch {
+	case set > 1:
+		return nil, fmt.Errorf("select the account by only one of address, label or index")
+	case selector.Address != "":
+		accData, err := wallet.GetAccountDataByAddress(selector.Address)
+		if err != nil {
+			return nil, fmt.Errorf("account with address %s error:%s", selector.Address, err)
+		}
+		return accData, nil
+	case selector.Label != "":
+		accData, err := wallet.GetAccountDataByLabel(selector.Label)
+		if err != nil {
+			return nil, fmt.Errorf("account with label %s error:%s", selector.Label, err)
+		}
+		return accData, nil
+	case selector.Index != 0:
+		accData, err := wallet.GetAccountDataByIndex(selector.Index)
+		if err != nil {
+			return nil, fmt.Errorf("account at index %d of %d error:%s", selector.Index, wallet.GetAccountCount(), err)
+		}
+		return accData, nil
+	default:
+		return wallet.GetDefaultAccountData()
+	}
+}
+
+//AccountLoader unlock wallet accounts with a password read once from a PasswordSource, so every
+//account must share that password
+type AccountLoader struct {
+	sdk      *sdk.OntologySdk
+	source   *PasswordSource
+	pwd      []byte
+	wallets  map[string]*sdk.Wallet
+	accounts map[string]*sdk.Account
+}
+
+func NewAccountLoader(ontSdk *sdk.OntologySdk, source *PasswordSource) *AccountLoader {
+	return &AccountLoader{
+		sdk:      ontSdk,
+		source:   source,
+		wallets:  make(map[string]*sdk.Wallet),
+		accounts: make(map[string]*sdk.Account),
+	}
+}
+
+//Load unlock the account of the wallet at path chosen by selector, an account loaded several times
+//is unlocked once
+func (this *AccountLoader) Load(path string, selector *AccountSelector) (*sdk.Account, error) {
+	accData, err := this.selectAccountData(path, selector)
 	if err != nil {
-		fmt.Println("open wallet error:", err)
-		return nil, false
+		return nil, err
+	}
+	if account, ok := this.accounts[accData.Address]; ok {
+		return account, nil
+	}
+	fmt.Println("relayer account:", accData.Address)
+	if this.pwd == nil {
+		this.pwd, err = this.source.GetPassword()
+		if err != nil {
+			return nil, fmt.Errorf("getPassword from %s error:%s", this.source.Type, err)
+		}
 	}
-	pwd, err := password.GetPassword()
+	account, err := accData.GetAccount(this.pwd)
 	if err != nil {
-		fmt.Println("getPassword error:", err)
-		return nil, false
+		return nil, fmt.Errorf("unlock account %s error:%s", accData.Address, err)
+	}
+	this.accounts[accData.Address] = account
+	return account, nil
+}
+
+//Address return the base58 address of the account of the wallet at path chosen by selector, without
+//unlocking it
+func (this *AccountLoader) Address(path string, selector *AccountSelector) (string, error) {
+	accData, err := this.selectAccountData(path, selector)
+	if err != nil {
+		return "", err
+	}
+	return accData.Address, nil
+}
+
+func (this *AccountLoader) selectAccountData(path string, selector *AccountSelector) (*sdk.AccountData, error) {
+	wallet, ok := this.wallets[path]
+	if !ok {
+		var err error
+		wallet, err = this.sdk.OpenWallet(path)
+		if err != nil {
+			return nil, fmt.Errorf("open wallet %s error:%s", path, err)
+		}
+		this.wallets[path] = wallet
+	}
+	accData, err := SelectAccountData(wallet, selector)
+	if err != nil {
+		return nil, fmt.Errorf("select account of wallet %s error:%s", path, err)
 	}
-	user, err := wallet.GetDefaultAccount(pwd)
+	return accData, nil
+}
+
+//Close clear the password from memory
+func (this *AccountLoader) Close() {
+	ClearPassword(this.pwd)
+	this.pwd = nil
+}
+
+//GetAccountByPassword unlock the account of the wallet at path chosen by selector with the password
+//read from source
+func GetAccountByPassword(sdk *sdk.OntologySdk, path string, selector *AccountSelector, source *PasswordSource) (*sdk.Account, bool) {
+	loader := NewAccountLoader(sdk, source)
+	defer loader.Close()
+	account, err := loader.Load(path, selector)
 	if err != nil {
-		fmt.Println("getDefaultAccount error:", err)
+		fmt.Println("load account error:", err)
 		return nil, false
 	}
-	return user, true
+	return account, true
 }
 
 func ConcatKey(args ...[]byte) []byte {
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectAccountData(t *testing.T) {
+	wallet, err := sdk.NewOntologySdk().OpenWallet("../wallet.dat")
+	assert.Nil(t, err)
+	defaultAcc, err := wallet.GetDefaultAccountData()
+	assert.Nil(t, err)
+
+	accData, err := SelectAccountData(wallet, &AccountSelector{})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultAcc.Address, accData.Address)
+	accData, err = SelectAccountData(wallet, &AccountSelector{Address: defaultAcc.Address})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultAcc.Address, accData.Address)
+	accData, err = SelectAccountData(wallet, &AccountSelector{Index: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultAcc.Address, accData.Address)
+
+	_, err = SelectAccountData(wallet, &AccountSelector{Index: 2})
+	assert.NotNil(t, err)
+	_, err = SelectAccountData(wallet, &AccountSelector{Address: "AKNiGT7B6xhWwmtBtibgXpHmjudJTinYpC"})
+	assert.NotNil(t, err)
+	_, err = SelectAccountData(wallet, &AccountSelector{Label: "relayer"})
+	assert.NotNil(t, err)
+	_, err = SelectAccountData(wallet, &AccountSelector{Address: defaultAcc.Address, Index: 1})
+	assert.NotNil(t, err)
+}
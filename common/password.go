@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/ontio/ontology/common/password"
+)
+
+const (
+	PASSWORD_SOURCE_TTY   = "tty"
+	PASSWORD_SOURCE_FILE  = "file"
+	PASSWORD_SOURCE_ENV   = "env"
+	PASSWORD_SOURCE_STDIN = "stdin"
+)
+
+//PasswordSource tell where the wallet password is read from
+type PasswordSource struct {
+	//one of the PASSWORD_SOURCE_* values, tty if empty
+	Type string
+	//file, named pipe or mounted secret read by the file source
+	File string
+	//environment variable read by the env source, it is unset once read
+	Env string
+}
+
+//GetPassword read the password from the source, the caller should ClearPassword it after use
+func (this *PasswordSource) GetPassword() ([]byte, error) {
+	return this.getPassword(os.Stdin)
+}
+
+func (this *PasswordSource) getPassword(stdin io.Reader) ([]byte, error) {
+	switch this.Type {
+	case "", PASSWORD_SOURCE_TTY:
+		return password.GetPassword()
+	case PASSWORD_SOURCE_FILE:
+		if this.File == "" {
+			return nil, fmt.Errorf("password source file but no password file given")
+		}
+		data, err := ioutil.ReadFile(this.File)
+		if err != nil {
+			return nil, fmt.Errorf("read password file error:%s", err)
+		}
+		return trimPassword(data), nil
+	case PASSWORD_SOURCE_ENV:
+		if this.Env == "" {
+			return nil, fmt.Errorf("password source env but no environment variable given")
+		}
+		value, ok := os.LookupEnv(this.Env)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", this.Env)
+		}
+		os.Unsetenv(this.Env)
+		return []byte(value), nil
+	case PASSWORD_SOURCE_STDIN:
+		line, err := bufio.NewReader(stdin).ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, fmt.Errorf("read password from stdin error:%s", err)
+		}
+		return trimPassword(line), nil
+	default:
+		return nil, fmt.Errorf("unknown password source %s", this.Type)
+	}
+}
+
+//trimPassword remove the line ending of a password read from a file or stdin
+func trimPassword(data []byte) []byte {
+	n := len(data)
+	if n > 0 && data[n-1] == '\n' {
+		n--
+		if n > 0 && data[n-1] == '\r' {
+			n--
+		}
+	}
+	for i := n; i < len(data); i++ {
+		data[i] = 0
+	}
+	return data[:n]
+}
+
+//ClearPassword overwrite the password in memory
+func ClearPassword(pwd []byte) {
+	for i := range pwd {
+		pwd[i] = 0
+	}
+}
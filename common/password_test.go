@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "password")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "password")
+	assert.Nil(t, ioutil.WriteFile(file, []byte("secret\r\n"), 0600))
+
+	pwd, err := (&PasswordSource{Type: PASSWORD_SOURCE_FILE, File: file}).GetPassword()
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", string(pwd))
+	ClearPassword(pwd)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0}, pwd)
+
+	_, err = (&PasswordSource{Type: PASSWORD_SOURCE_FILE, File: filepath.Join(dir, "missing")}).GetPassword()
+	assert.NotNil(t, err)
+	_, err = (&PasswordSource{Type: PASSWORD_SOURCE_FILE}).GetPassword()
+	assert.NotNil(t, err)
+
+	os.Setenv("TEST_WALLET_PASSWORD", "from env")
+	pwd, err = (&PasswordSource{Type: PASSWORD_SOURCE_ENV, Env: "TEST_WALLET_PASSWORD"}).GetPassword()
+	assert.Nil(t, err)
+	assert.Equal(t, "from env", string(pwd))
+	_, ok := os.LookupEnv("TEST_WALLET_PASSWORD")
+	assert.False(t, ok)
+	_, err = (&PasswordSource{Type: PASSWORD_SOURCE_ENV, Env: "TEST_WALLET_PASSWORD"}).GetPassword()
+	assert.NotNil(t, err)
+
+	source := &PasswordSource{Type: PASSWORD_SOURCE_STDIN}
+	pwd, err = source.getPassword(strings.NewReader("from stdin\nignored\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "from stdin", string(pwd))
+	pwd, err = source.getPassword(strings.NewReader("no newline"))
+	assert.Nil(t, err)
+	assert.Equal(t, "no newline", string(pwd))
+	_, err = source.getPassword(strings.NewReader(""))
+	assert.NotNil(t, err)
+
+	_, err = (&PasswordSource{Type: "keyring"}).GetPassword()
+	assert.NotNil(t, err)
+}
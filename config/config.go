@@ -20,57 +20,337 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strconv"
 )
 
 const (
 	DEFAULT_CONFIG_FILE_NAME = "./config.json"
 	DEFAULT_LOG_LEVEL        = 2
+	DEFAULT_CHECKPOINT_PATH  = "./CheckpointDB"
+	DEFAULT_POLLING_INTERVAL = 1000
+	DEFAULT_SHUTDOWN_TIMEOUT = 30
+	DEFAULT_RETRY_ATTEMPTS   = 5
+	DEFAULT_RETRY_BASE_DELAY = 500
+	DEFAULT_RETRY_MAX_DELAY  = 30000
+	DEFAULT_RETRY_JITTER     = 0.2
+	DEFAULT_CONFIRM_TIMEOUT  = 60000
+	DEFAULT_PASSWORD_SOURCE  = "tty"
+	DEFAULT_PASSWORD_ENV     = "CCC_WALLET_PASSWORD"
+	DEFAULT_SIGNER_ADDRESS   = "127.0.0.1:20500"
+	DEFAULT_QUEUE_TIMEOUT    = 600
+	DEFAULT_READY_MAX_LAG    = 100
+	DEFAULT_READY_TIMEOUT    = 10
+	DEFAULT_STATUS_BLOCKS    = 100
 )
 
+//go:generate go run ./examplegen
+
 //Default config instance
 var DefConfig = NewConfig()
 
+//AccountConfig select a wallet account
+type AccountConfig struct {
+	WalletFile string //WalletFile of Config if empty
+	Address    string //base58 address, the default account of the wallet if no field is set
+	Label      string
+	Index      int //index of the account in the wallet, starting from 1
+	//url of a remote signer holding the key of Address, the account is unlocked from WalletFile if empty
+	SignerAddress   string
+	SignerTokenFile string //file holding the API token of the remote signer
+}
+
+//MultiSigConfig describe an M-of-N multi-signature account co-signed by operators
+type MultiSigConfig struct {
+	M        int
+	PubKeys  []string         //hex public keys of the N operators
+	Accounts []*AccountConfig //operator accounts signing locally
+	//directory where the txs wait for the signatures of the other operators, required if Accounts
+	//hold less than M keys
+	QueueDir     string
+	QueueTimeout uint64 //seconds to wait for the signatures of the queue
+}
+
+//ChainConfig describe a chain the relayer connects to
+type ChainConfig struct {
+	ChainID        uint64
+	NetworkID      uint32 //network id reported by the node, checked by the live checks if not zero
+	JsonRpcAddress string
+	WsAddress      string
+	GasPrice       uint64
+	GasLimit       uint64
+	Account        *AccountConfig //signer of the txs submitted to the chain, the global account if nil
+	HeaderAccount  *AccountConfig //signer of the SYNC_BLOCK_HEADER txs, Account if nil
+	ProofAccount   *AccountConfig //signer of the PROCESS_CROSS_CHAIN_TX txs, Account if nil
+	//multi-signature paying the SYNC_BLOCK_HEADER and PROCESS_CROSS_CHAIN_TX txs, instead of the
+	//accounts above if set
+	MultiSig *MultiSigConfig
+}
+
+//RouteConfig describe a relay direction between two chains of Chains
+type RouteConfig struct {
+	FromChainID uint64
+	ToChainID   uint64
+}
+
 //Config object used by ontology-instance
 type Config struct {
+	//single side chain settings, used when Chains is empty
 	MainJsonRpcAddress string
 	SideJsonRpcAddress string
-	MainChainID        uint64
+	MainWsAddress      string
+	SideWsAddress      string
 	SideChainID        uint64
-	WalletFile         string
 	GasPrice           uint64
 	GasLimit           uint64
+
+	Chains []*ChainConfig
+	//relay directions, main chain to and from every side chain if empty
+	Routes []*RouteConfig
+
+	LogLevel         int    `flag:"loglevel"` //0:Trace 1:Debug 2:Info 3:Warn 4:Error 5:Fatal 6:MaxLevel
+	PollingInterval  uint64 //milliseconds between height polls when websocket is unavailable
+	MainChainID      uint64
+	WalletFile       string
+	AccountAddress   string `flag:"account" group:"account"` //base58 address of the relayer account, the default account if no account option is set
+	AccountLabel     string `group:"account"`
+	AccountIndex     int    `group:"account"` //index of the account in WalletFile, starting from 1
+	PasswordSource   string //tty, file, env or stdin
+	PasswordFile     string //password file, named pipe or mounted secret of the file source
+	PasswordEnv      string //environment variable of the env source
+	CheckpointPath   string
+	ShutdownTimeout  uint64 //seconds to wait for the blocks in flight on exit
+	RetryMaxAttempts uint64
+	RetryBaseDelay   uint64 //milliseconds before the first retry, doubled for each next one
+	RetryMaxDelay    uint64 //milliseconds
+	RetryJitter      float64
+	ConfirmTimeout   uint64 //milliseconds to wait for a submitted tx before submitting it again
+	MonitorAddress   string //address of the http server exposing /metrics, /healthz and /readyz as host:port, disabled if empty
+	ReadyMaxLag      uint64 //blocks a route may lag behind its source chain and stay ready, 0 for no limit
+	ReadyTimeout     uint64 //minutes a route may go without relaying or being caught up and stay ready, 0 for no limit
+	AdminAddress     string //loopback address of the admin api as host:port, disabled if empty
+	AdminTokenFile   string //file holding the token the admin api clients must present
 }
 
 //NewConfig retuen a TestConfig instance
 func NewConfig() *Config {
-	return &Config{}
+	return &Config{
+		LogLevel:         DEFAULT_LOG_LEVEL,
+		CheckpointPath:   DEFAULT_CHECKPOINT_PATH,
+		PollingInterval:  DEFAULT_POLLING_INTERVAL,
+		ShutdownTimeout:  DEFAULT_SHUTDOWN_TIMEOUT,
+		RetryMaxAttempts: DEFAULT_RETRY_ATTEMPTS,
+		RetryBaseDelay:   DEFAULT_RETRY_BASE_DELAY,
+		RetryMaxDelay:    DEFAULT_RETRY_MAX_DELAY,
+		RetryJitter:      DEFAULT_RETRY_JITTER,
+		ConfirmTimeout:   DEFAULT_CONFIRM_TIMEOUT,
+		PasswordSource:   DEFAULT_PASSWORD_SOURCE,
+		PasswordEnv:      DEFAULT_PASSWORD_ENV,
+		ReadyMaxLag:      DEFAULT_READY_MAX_LAG,
+		ReadyTimeout:     DEFAULT_READY_TIMEOUT,
+	}
 }
 
-//Init TestConfig with a config file
+//Init TestConfig with a config file, in the format of its extension
 func (this *Config) Init(fileName string) error {
-	err := this.loadConfig(fileName)
+	return this.InitWithFormat(fileName, "")
+}
+
+//InitWithFormat init TestConfig with a config file in format, one of the FORMAT_* values or empty to
+//use the format of the file extension
+func (this *Config) InitWithFormat(fileName, format string) error {
+	if format == "" {
+		format = GetFormat(fileName)
+	}
+	err := this.loadConfig(fileName, format)
 	if err != nil {
 		return fmt.Errorf("loadConfig error:%s", err)
 	}
 	return nil
 }
 
-func (this *Config) loadConfig(fileName string) error {
+//GetChains return Chains, or the main and side chain of the single side chain settings
+func (this *Config) GetChains() []*ChainConfig {
+	if len(this.Chains) != 0 {
+		return this.Chains
+	}
+	return []*ChainConfig{
+		{
+			ChainID:        this.MainChainID,
+			JsonRpcAddress: this.MainJsonRpcAddress,
+			WsAddress:      this.MainWsAddress,
+			GasPrice:       this.GasPrice,
+			GasLimit:       this.GasLimit,
+		},
+		{
+			ChainID:        this.SideChainID,
+			JsonRpcAddress: this.SideJsonRpcAddress,
+			WsAddress:      this.SideWsAddress,
+			GasPrice:       this.GasPrice,
+			GasLimit:       this.GasLimit,
+		},
+	}
+}
+
+//GetChain return the chain of GetChains with id chainID, nil if not found
+func (this *Config) GetChain(chainID uint64) *ChainConfig {
+	for _, chain := range this.GetChains() {
+		if chain.ChainID == chainID {
+			return chain
+		}
+	}
+	return nil
+}
+
+//FindChain return the chain of GetChains named name: main for the main chain, side for the only side
+//chain, or the chain id
+func (this *Config) FindChain(name string) (*ChainConfig, error) {
+	switch name {
+	case "main":
+		chain := this.GetChain(this.MainChainID)
+		if chain == nil {
+			return nil, fmt.Errorf("main chain %d is not configured", this.MainChainID)
+		}
+		return chain, nil
+	case "side":
+		var side *ChainConfig
+		for _, chain := range this.GetChains() {
+			if chain.ChainID == this.MainChainID {
+				continue
+			}
+			if side != nil {
+				return nil, fmt.Errorf("several side chains are configured, use a chain id")
+			}
+			side = chain
+		}
+		if side == nil {
+			return nil, fmt.Errorf("no side chain is configured")
+		}
+		return side, nil
+	}
+	chainID, err := strconv.ParseUint(name, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("chain %q is not main, side or a chain id", name)
+	}
+	chain := this.GetChain(chainID)
+	if chain == nil {
+		return nil, fmt.Errorf("chain %d is not configured", chainID)
+	}
+	return chain, nil
+}
+
+//GetAccount return the global relayer account
+func (this *Config) GetAccount() *AccountConfig {
+	return &AccountConfig{
+		WalletFile: this.WalletFile,
+		Address:    this.AccountAddress,
+		Label:      this.AccountLabel,
+		Index:      this.AccountIndex,
+	}
+}
+
+//GetHeaderAccount return the account signing the headers synced to chain
+func (this *Config) GetHeaderAccount(chain *ChainConfig) *AccountConfig {
+	return this.getChainAccount(chain.HeaderAccount, chain.Account)
+}
+
+//GetProofAccount return the account signing the cross chain proofs sent to chain
+func (this *Config) GetProofAccount(chain *ChainConfig) *AccountConfig {
+	return this.getChainAccount(chain.ProofAccount, chain.Account)
+}
+
+//GetMultiSigAccounts return the local operator accounts of multiSig
+func (this *Config) GetMultiSigAccounts(multiSig *MultiSigConfig) []*AccountConfig {
+	accounts := make([]*AccountConfig, 0, len(multiSig.Accounts))
+	for _, account := range multiSig.Accounts {
+		accounts = append(accounts, this.getChainAccount(account))
+	}
+	return accounts
+}
+
+//getChainAccount return the first account set, or the global account
+func (this *Config) getChainAccount(accounts ...*AccountConfig) *AccountConfig {
+	for _, account := range accounts {
+		if account == nil {
+			continue
+		}
+		result := *account
+		if result.WalletFile == "" {
+			result.WalletFile = this.WalletFile
+		}
+		return &result
+	}
+	return this.GetAccount()
+}
+
+//GetRoutes return Routes, or the routes from the main chain to every other chain and back
+func (this *Config) GetRoutes() []*RouteConfig {
+	if len(this.Routes) != 0 {
+		return this.Routes
+	}
+	routes := make([]*RouteConfig, 0)
+	for _, chain := range this.GetChains() {
+		if chain.ChainID == this.MainChainID {
+			continue
+		}
+		routes = append(routes,
+			&RouteConfig{FromChainID: this.MainChainID, ToChainID: chain.ChainID},
+			&RouteConfig{FromChainID: chain.ChainID, ToChainID: this.MainChainID})
+	}
+	return routes
+}
+
+func (this *Config) loadConfig(fileName, format string) error {
 	data, err := this.readFile(fileName)
 	if err != nil {
 		return err
 	}
-	err = json.Unmarshal(data, this)
+	if format != FORMAT_JSON {
+		//decode every format as json, so that they share the field names, defaults and strictness
+		jsonData, err := toJson(data, format)
+		if err != nil {
+			return fmt.Errorf("decode %s error:%s", fileName, err)
+		}
+		return this.decodeJson(jsonData, func(err error) string { return err.Error() })
+	}
+	return this.decodeJson(data, func(err error) string { return describeJsonError(data, err) })
+}
+
+//decodeJson decode data into the config, describe format the decoding errors
+func (this *Config) decodeJson(data []byte, describe func(err error) string) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	//reject misspelled fields instead of leaving them to their default
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(this)
 	if err != nil {
-		return fmt.Errorf("json.Unmarshal TestConfig:%s error:%s", data, err)
+		return fmt.Errorf("decode error:%s", describe(err))
 	}
 	return nil
 }
 
+//describeJsonError locate err in data when it has an offset
+func describeJsonError(data []byte, err error) string {
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	default:
+		return err.Error()
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset - int64(bytes.LastIndexByte(data[:offset], '\n'))
+	return fmt.Sprintf("line %d column %d: %s", line, column, err)
+}
+
 func (this *Config) readFile(fileName string) ([]byte, error) {
 	file, err := os.OpenFile(fileName, os.O_RDONLY, 0666)
 	if err != nil {
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChainsAndRoutes(t *testing.T) {
+	cfg := NewConfig()
+	cfg.MainChainID = 0
+	cfg.SideChainID = 1
+	cfg.MainJsonRpcAddress = "http://127.0.0.1:20336"
+	cfg.SideJsonRpcAddress = "http://127.0.0.1:30336"
+	cfg.GasLimit = 20000
+	chains := cfg.GetChains()
+	assert.Equal(t, 2, len(chains))
+	assert.Equal(t, &ChainConfig{ChainID: 1, JsonRpcAddress: "http://127.0.0.1:30336", GasLimit: 20000}, cfg.GetChain(1))
+	assert.Equal(t, []*RouteConfig{{FromChainID: 0, ToChainID: 1}, {FromChainID: 1, ToChainID: 0}}, cfg.GetRoutes())
+
+	cfg.Chains = []*ChainConfig{{ChainID: 0}, {ChainID: 1}, {ChainID: 2}}
+	assert.Equal(t, 4, len(cfg.GetRoutes()))
+	assert.Nil(t, cfg.GetChain(3))
+
+	cfg.Routes = []*RouteConfig{{FromChainID: 1, ToChainID: 2}}
+	assert.Equal(t, cfg.Routes, cfg.GetRoutes())
+}
+
+func TestFindChain(t *testing.T) {
+	cfg := NewConfig()
+	cfg.MainChainID = 1
+	cfg.Chains = []*ChainConfig{{ChainID: 1}, {ChainID: 2}}
+	chain, err := cfg.FindChain("main")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), chain.ChainID)
+	chain, err = cfg.FindChain("side")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), chain.ChainID)
+	chain, err = cfg.FindChain("2")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), chain.ChainID)
+
+	cfg.Chains = append(cfg.Chains, &ChainConfig{ChainID: 3})
+	_, err = cfg.FindChain("side")
+	assert.EqualError(t, err, "several side chains are configured, use a chain id")
+	_, err = cfg.FindChain("4")
+	assert.EqualError(t, err, "chain 4 is not configured")
+	_, err = cfg.FindChain("sidechain")
+	assert.EqualError(t, err, `chain "sidechain" is not main, side or a chain id`)
+}
+
+func TestGetChainAccounts(t *testing.T) {
+	cfg := NewConfig()
+	cfg.WalletFile = "./wallet.dat"
+	cfg.AccountLabel = "relayer"
+	chain := &ChainConfig{ChainID: 1}
+	assert.Equal(t, &AccountConfig{WalletFile: "./wallet.dat", Label: "relayer"}, cfg.GetHeaderAccount(chain))
+	assert.Equal(t, &AccountConfig{WalletFile: "./wallet.dat", Label: "relayer"}, cfg.GetProofAccount(chain))
+
+	chain.Account = &AccountConfig{Index: 2}
+	chain.ProofAccount = &AccountConfig{WalletFile: "./proof.dat", Address: "AMAx993nE6NEqZjwBssUfopxnnvTdob9ij"}
+	assert.Equal(t, &AccountConfig{WalletFile: "./wallet.dat", Index: 2}, cfg.GetHeaderAccount(chain))
+	assert.Equal(t, &AccountConfig{WalletFile: "./proof.dat", Address: "AMAx993nE6NEqZjwBssUfopxnnvTdob9ij"}, cfg.GetProofAccount(chain))
+	assert.Equal(t, "", chain.Account.WalletFile)
+}
+
+func TestGetMultiSigAccounts(t *testing.T) {
+	cfg := NewConfig()
+	cfg.WalletFile = "./wallet.dat"
+	multiSig := &MultiSigConfig{
+		M:        2,
+		Accounts: []*AccountConfig{{Index: 1}, {WalletFile: "./operator.dat", Label: "operator"}},
+	}
+	assert.Equal(t, []*AccountConfig{{WalletFile: "./wallet.dat", Index: 1}, {WalletFile: "./operator.dat", Label: "operator"}},
+		cfg.GetMultiSigAccounts(multiSig))
+}
@@ -0,0 +1,300 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//examplegen write config.yaml and config.toml, annotated examples of the relayer config with the values of
+//config.json and the field comments of config.go. Run it with go generate in the config package
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ontio/crossChainClient/config"
+)
+
+var (
+	source = flag.String("source", "config.go", "go file declaring the config types")
+	values = flag.String("values", "../config.json", "json config providing the example values")
+	outDir = flag.String("out", "..", "directory of the generated examples")
+)
+
+func main() {
+	flag.Parse()
+	docs, err := parseDocs(*source)
+	if err != nil {
+		fail(err)
+	}
+	cfg := config.NewConfig()
+	err = cfg.Init(*values)
+	if err != nil {
+		fail(err)
+	}
+	header := []string{
+		fmt.Sprintf("# Example relayer config generated by config/examplegen from %s,", filepath.Base(*values)),
+		"# commented out sections are optional",
+	}
+	examples := map[string][]string{
+		"config.yaml": yamlStruct(reflect.ValueOf(cfg).Elem(), true, docs),
+		"config.toml": tomlTable(reflect.ValueOf(cfg).Elem(), "", false, true, docs),
+	}
+	for name, lines := range examples {
+		data := strings.Join(append(header, lines...), "\n") + "\n"
+		err = ioutil.WriteFile(filepath.Join(*outDir, name), []byte(data), 0644)
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "examplegen:", err)
+	os.Exit(1)
+}
+
+//parseDocs return the comments of the struct fields declared in file, keyed by "Type.Field"
+func parseDocs(file string) (map[string][]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string][]string)
+	ast.Inspect(f, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, field := range st.Fields.List {
+			lines := make([]string, 0)
+			for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+				if group == nil {
+					continue
+				}
+				for _, line := range strings.Split(strings.TrimSpace(group.Text()), "\n") {
+					lines = append(lines, strings.TrimSpace(line))
+				}
+			}
+			for _, name := range field.Names {
+				docs[spec.Name.Name+"."+name.Name] = lines
+			}
+		}
+		return false
+	})
+	return docs, nil
+}
+
+func comments(docs []string) []string {
+	lines := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		lines = append(lines, "# "+doc)
+	}
+	return lines
+}
+
+//commentOut comment the lines which are not comments yet
+func commentOut(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			result = append(result, line)
+			continue
+		}
+		result = append(result, line[:len(line)-len(trimmed)]+"# "+trimmed)
+	}
+	return result
+}
+
+func indent(lines []string, prefix string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, prefix+line)
+	}
+	return result
+}
+
+//isTable return whether v is rendered as a nested structure rather than a value
+func isTable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return t.Elem().Kind() == reflect.Struct
+	case reflect.Slice:
+		return isTable(t.Elem()) || t.Elem().Kind() == reflect.Struct
+	case reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+//scalar format a value the same way for yaml and toml
+func scalar(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, scalar(v.Index(i)))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+//element return the struct of a table field value, and whether it is only an example of an empty value
+func elements(v reflect.Value) ([]reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return []reflect.Value{reflect.New(v.Type().Elem()).Elem()}, true
+		}
+		return []reflect.Value{v.Elem()}, false
+	case reflect.Slice:
+		if v.Len() == 0 {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if elem.Kind() == reflect.Ptr {
+				elem = reflect.New(elem.Type().Elem()).Elem()
+			}
+			return []reflect.Value{elem}, true
+		}
+		result := make([]reflect.Value, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			result = append(result, elem)
+		}
+		return result, false
+	default:
+		return []reflect.Value{v}, false
+	}
+}
+
+//yamlStruct render the struct v, with commented out examples of its empty optional fields if examples
+//is set
+func yamlStruct(v reflect.Value, examples bool, docs map[string][]string) []string {
+	lines := make([]string, 0)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		doc := comments(docs[t.Name()+"."+field.Name])
+		value := v.Field(i)
+		if !isTable(field.Type) {
+			lines = append(lines, doc...)
+			lines = append(lines, fmt.Sprintf("%s: %s", field.Name, scalar(value)))
+			continue
+		}
+		elems, example := elements(value)
+		if example && !examples {
+			continue
+		}
+		lines = append(lines, doc...)
+		block := make([]string, 0)
+		for i, elem := range elems {
+			item := yamlStruct(elem, examples && i == 0, docs)
+			if field.Type.Kind() == reflect.Slice {
+				item = yamlItem(item)
+			}
+			block = append(block, indent(item, "  ")...)
+		}
+		block = append([]string{field.Name + ":"}, block...)
+		if example {
+			block = commentOut(block)
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+//yamlItem turn the lines of a struct into a list item
+func yamlItem(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	first := true
+	for _, line := range lines {
+		if first && !strings.HasPrefix(line, "#") {
+			result = append(result, "- "+line)
+			first = false
+			continue
+		}
+		result = append(result, "  "+line)
+	}
+	return result
+}
+
+//tomlTable render the struct v as the table path, listing the values before the sub tables as toml
+//requires, with commented out examples of its empty optional fields if examples is set
+func tomlTable(v reflect.Value, path string, array, examples bool, docs map[string][]string) []string {
+	lines := make([]string, 0)
+	if path != "" {
+		if array {
+			lines = append(lines, "[["+path+"]]")
+		} else {
+			lines = append(lines, "["+path+"]")
+		}
+	}
+	t := v.Type()
+	tables := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		doc := comments(docs[t.Name()+"."+field.Name])
+		value := v.Field(i)
+		if !isTable(field.Type) {
+			lines = append(lines, doc...)
+			lines = append(lines, fmt.Sprintf("%s = %s", field.Name, scalar(value)))
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		elems, example := elements(value)
+		if example && !examples {
+			continue
+		}
+		block := make([]string, 0)
+		for i, elem := range elems {
+			block = append(block, "")
+			if i == 0 {
+				block = append(block, doc...)
+			}
+			block = append(block, tomlTable(elem, fieldPath, field.Type.Kind() == reflect.Slice, examples && i == 0, docs)...)
+		}
+		if example {
+			block = commentOut(block)
+		}
+		tables = append(tables, block...)
+	}
+	return append(lines, tables...)
+}
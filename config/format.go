@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+//config file formats
+const (
+	FORMAT_JSON = "json"
+	FORMAT_YAML = "yaml"
+	FORMAT_TOML = "toml"
+)
+
+//GetFormat return the format of a config file from its extension, json if unknown
+func GetFormat(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return FORMAT_YAML
+	case ".toml":
+		return FORMAT_TOML
+	default:
+		return FORMAT_JSON
+	}
+}
+
+//toJson convert a yaml or toml document to json
+func toJson(data []byte, format string) ([]byte, error) {
+	var doc interface{}
+	switch format {
+	case FORMAT_YAML:
+		err := yaml.UnmarshalStrict(data, &doc)
+		if err != nil {
+			return nil, err
+		}
+		doc, err = stringKeys(doc)
+		if err != nil {
+			return nil, err
+		}
+	case FORMAT_TOML:
+		table := make(map[string]interface{})
+		_, err := toml.Decode(string(data), &table)
+		if err != nil {
+			return nil, err
+		}
+		doc = table
+	default:
+		return nil, fmt.Errorf("unknown config format %q, expected %s, %s or %s", format, FORMAT_JSON,
+			FORMAT_YAML, FORMAT_TOML)
+	}
+	if doc == nil {
+		//empty document
+		doc = map[string]interface{}{}
+	}
+	return json.Marshal(doc)
+}
+
+//stringKeys convert the yaml maps of doc to json objects
+func stringKeys(doc interface{}) (interface{}, error) {
+	switch v := doc.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("key %v is not a string", key)
+			}
+			value, err := stringKeys(value)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = value
+		}
+		return m, nil
+	case []interface{}:
+		for i, value := range v {
+			value, err := stringKeys(value)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = value
+		}
+		return v, nil
+	default:
+		return doc, nil
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFormat(t *testing.T) {
+	assert.Equal(t, FORMAT_JSON, GetFormat("./config.json"))
+	assert.Equal(t, FORMAT_JSON, GetFormat("./config"))
+	assert.Equal(t, FORMAT_YAML, GetFormat("./config.yml"))
+	assert.Equal(t, FORMAT_YAML, GetFormat("./CONFIG.YAML"))
+	assert.Equal(t, FORMAT_TOML, GetFormat("./config.toml"))
+}
+
+//the examples generated from config.json hold the same config
+func TestLoadExamples(t *testing.T) {
+	expected := NewConfig()
+	assert.Nil(t, expected.Init("../config.json"))
+	for _, example := range []string{"../config.yaml", "../config.toml"} {
+		cfg := NewConfig()
+		assert.Nil(t, cfg.Init(example), example)
+		assert.Equal(t, expected, cfg, example)
+	}
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, ioutil.WriteFile(path, []byte(data), 0600))
+		return path
+	}
+
+	cfg := NewConfig()
+	path := write("config.yml", "# comment\nGasLimit: 20000\nChains:\n  - ChainID: 1\n    GasPrice: 500\n")
+	assert.Nil(t, cfg.Init(path))
+	assert.Equal(t, uint64(20000), cfg.GasLimit)
+	assert.Equal(t, []*ChainConfig{{ChainID: 1, GasPrice: 500}}, cfg.Chains)
+	assert.Equal(t, uint64(DEFAULT_POLLING_INTERVAL), cfg.PollingInterval)
+
+	cfg = NewConfig()
+	path = write("config.toml", "GasLimit = 20000\n[[Chains]]\nChainID = 1\nGasPrice = 500\n")
+	assert.Nil(t, cfg.Init(path))
+	assert.Equal(t, uint64(20000), cfg.GasLimit)
+	assert.Equal(t, []*ChainConfig{{ChainID: 1, GasPrice: 500}}, cfg.Chains)
+
+	//the format flag wins over the extension
+	path = write("config.conf", "GasLimit = 30000\n")
+	assert.NotNil(t, NewConfig().Init(path))
+	cfg = NewConfig()
+	assert.Nil(t, cfg.InitWithFormat(path, FORMAT_TOML))
+	assert.Equal(t, uint64(30000), cfg.GasLimit)
+	assert.NotNil(t, NewConfig().InitWithFormat(path, "xml"))
+
+	for name, data := range map[string]string{
+		"typo.yaml":   "GasLimt: 20000\n",
+		"typo.toml":   "GasLimt = 20000\n",
+		"type.yaml":   "GasLimit: high\n",
+		"syntax.toml": "GasLimit = \n",
+	} {
+		assert.NotNil(t, NewConfig().Init(write(name, data)), name)
+	}
+}
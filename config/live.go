@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"fmt"
+
+	sdk "github.com/ontio/ontology-go-sdk"
+)
+
+//CheckLive check that the node of every chain answers and is on its own network with the configured
+//NetworkID, the returned error is a *ValidationError listing all the problems found
+func (this *Config) CheckLive() error {
+	problems := &ValidationError{}
+	genesis := make(map[string]uint64)
+	for _, chain := range this.GetChains() {
+		name := fmt.Sprintf("chain %d", chain.ChainID)
+		ontSdk := sdk.NewOntologySdk()
+		ontSdk.NewRpcClient().SetAddress(chain.JsonRpcAddress)
+		height, err := ontSdk.GetCurrentBlockHeight()
+		if err != nil {
+			problems.add("%s: node %s is unreachable: %s", name, chain.JsonRpcAddress, err)
+			continue
+		}
+		if chain.NetworkID != 0 {
+			networkID, err := ontSdk.GetNetworkId()
+			if err != nil {
+				problems.add("%s: GetNetworkId error: %s", name, err)
+			} else if networkID != chain.NetworkID {
+				problems.add("%s: node %s reports network id %d, expected %d", name, chain.JsonRpcAddress,
+					networkID, chain.NetworkID)
+			}
+		}
+		hash, err := ontSdk.GetBlockHash(0)
+		if err != nil {
+			problems.add("%s: GetBlockHash error: %s", name, err)
+			continue
+		}
+		if other, ok := genesis[hash.ToHexString()]; ok {
+			problems.add("%s: node %s is on the same network as chain %d", name, chain.JsonRpcAddress, other)
+			continue
+		}
+		genesis[hash.ToHexString()] = chain.ChainID
+		fmt.Printf("%s: node %s is at height %d\n", name, chain.JsonRpcAddress, height)
+	}
+	return problems.result()
+}
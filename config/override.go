@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//ENV_PREFIX prefix the environment variables overriding config fields
+const ENV_PREFIX = "CCC_"
+
+//ConfigField is a Config field overridable from the environment and the command line. The
+//configuration is merged with the precedence flags > environment > config file > defaults.
+//
+//Scalar fields take their plain value, Chains and Routes take the same json as in the config file.
+//The environment variable is CCC_ and the field name in upper snake case, e.g. CCC_GAS_LIMIT, and
+//the flag is the field name in kebab case, e.g. --gas-limit, unless set by the flag tag. Setting one
+//field of a group, such as the account selectors, in a layer clears the other fields of the group
+//set by the layers below
+type ConfigField struct {
+	Name  string
+	Env   string
+	Flag  string
+	group string
+	index int
+}
+
+var configFields = newConfigFields()
+
+func newConfigFields() []*ConfigField {
+	configType := reflect.TypeOf(Config{})
+	fields := make([]*ConfigField, 0, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		words := splitWords(field.Name)
+		flag := field.Tag.Get("flag")
+		if flag == "" {
+			flag = strings.ToLower(strings.Join(words, "-"))
+		}
+		fields = append(fields, &ConfigField{
+			Name:  field.Name,
+			Env:   ENV_PREFIX + strings.ToUpper(strings.Join(words, "_")),
+			Flag:  flag,
+			group: field.Tag.Get("group"),
+			index: i,
+		})
+	}
+	return fields
+}
+
+//ConfigFields return the overridable fields of Config
+func ConfigFields() []*ConfigField {
+	return configFields
+}
+
+//GetConfigField return the field named name, nil if not found
+func GetConfigField(name string) *ConfigField {
+	for _, field := range configFields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+//Override apply a configuration layer, lookup return the raw value of a field and whether the layer
+//sets it
+func (this *Config) Override(lookup func(field *ConfigField) (string, bool)) error {
+	values := make(map[*ConfigField]string)
+	groups := make(map[string]bool)
+	for _, field := range configFields {
+		value, ok := lookup(field)
+		if !ok {
+			continue
+		}
+		values[field] = value
+		if field.group != "" {
+			groups[field.group] = true
+		}
+	}
+	config := reflect.ValueOf(this).Elem()
+	for _, field := range configFields {
+		if _, ok := values[field]; !ok && groups[field.group] {
+			fieldValue := config.Field(field.index)
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		}
+	}
+	for _, field := range configFields {
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+		err := setValue(config.Field(field.index), value)
+		if err != nil {
+			return fmt.Errorf("%s value %q error:%s", field.Name, value, err)
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	default:
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		v := reflect.New(field.Type())
+		if err := decoder.Decode(v.Interface()); err != nil {
+			return err
+		}
+		field.Set(v.Elem())
+	}
+	return nil
+}
+
+//splitWords split a CamelCase name, keeping acronyms such as ID in one word
+func splitWords(name string) []string {
+	runes := []rune(name)
+	words := make([]string, 0)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+//Redacted return a copy of the config safe to print, without the passwords of the urls
+func (this *Config) Redacted() (*Config, error) {
+	data, err := json.Marshal(this)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal error:%s", err)
+	}
+	redacted := &Config{}
+	err = json.NewDecoder(bytes.NewReader(data)).Decode(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error:%s", err)
+	}
+	for _, address := range []*string{&redacted.MainJsonRpcAddress, &redacted.SideJsonRpcAddress,
+		&redacted.MainWsAddress, &redacted.SideWsAddress} {
+		*address = redactURL(*address)
+	}
+	redactAccount := func(account *AccountConfig) {
+		if account != nil {
+			account.SignerAddress = redactURL(account.SignerAddress)
+		}
+	}
+	for _, chain := range redacted.Chains {
+		chain.JsonRpcAddress = redactURL(chain.JsonRpcAddress)
+		chain.WsAddress = redactURL(chain.WsAddress)
+		redactAccount(chain.Account)
+		redactAccount(chain.HeaderAccount)
+		redactAccount(chain.ProofAccount)
+		if chain.MultiSig != nil {
+			for _, account := range chain.MultiSig.Accounts {
+				redactAccount(account)
+			}
+		}
+	}
+	return redacted, nil
+}
+
+func redactURL(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || u.User == nil {
+		return address
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
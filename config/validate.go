@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ontio/crossChainClient/common"
+	"github.com/ontio/crossChainClient/log"
+	"github.com/ontio/ontology-crypto/keypair"
+	ocommon "github.com/ontio/ontology/common"
+)
+
+//ValidationError list every problem found in a config
+type ValidationError struct {
+	Problems []string
+}
+
+func (this *ValidationError) Error() string {
+	return fmt.Sprintf("%d config problems:\n  %s", len(this.Problems), strings.Join(this.Problems, "\n  "))
+}
+
+func (this *ValidationError) add(format string, args ...interface{}) {
+	problem := fmt.Sprintf(format, args...)
+	for _, p := range this.Problems {
+		if p == problem {
+			return
+		}
+	}
+	this.Problems = append(this.Problems, problem)
+}
+
+func (this *ValidationError) result() error {
+	if len(this.Problems) == 0 {
+		return nil
+	}
+	return this
+}
+
+//Validate check the consistency of the config, the returned error is a *ValidationError listing all
+//the problems found
+func (this *Config) Validate() error {
+	problems := &ValidationError{}
+	chains := this.GetChains()
+	if len(chains) < 2 {
+		problems.add("at least two chains are required, got %d", len(chains))
+	}
+	seen := make(map[uint64]bool)
+	for _, chain := range chains {
+		name := fmt.Sprintf("chain %d", chain.ChainID)
+		if seen[chain.ChainID] {
+			problems.add("%s: duplicated chain id", name)
+		}
+		seen[chain.ChainID] = true
+		checkURL(problems, name+" JsonRpcAddress", chain.JsonRpcAddress, true, "http", "https")
+		checkURL(problems, name+" WsAddress", chain.WsAddress, false, "ws", "wss")
+		if chain.GasLimit == 0 {
+			problems.add("%s: GasLimit is 0", name)
+		}
+		if chain.MultiSig != nil {
+			this.checkMultiSig(problems, name+" MultiSig", chain.MultiSig)
+			continue
+		}
+		this.checkAccount(problems, name+" header account", this.GetHeaderAccount(chain))
+		this.checkAccount(problems, name+" proof account", this.GetProofAccount(chain))
+	}
+	if len(chains) != 0 && !seen[this.MainChainID] {
+		problems.add("MainChainID %d is not a configured chain", this.MainChainID)
+	}
+
+	routes := make(map[RouteConfig]bool)
+	for _, route := range this.GetRoutes() {
+		name := fmt.Sprintf("route %d->%d", route.FromChainID, route.ToChainID)
+		if route.FromChainID == route.ToChainID {
+			problems.add("%s: source and destination are the same chain", name)
+		}
+		if !seen[route.FromChainID] {
+			problems.add("%s: chain %d is not configured", name, route.FromChainID)
+		}
+		if !seen[route.ToChainID] {
+			problems.add("%s: chain %d is not configured", name, route.ToChainID)
+		}
+		if routes[*route] {
+			problems.add("%s: duplicated route", name)
+		}
+		routes[*route] = true
+	}
+
+	switch this.PasswordSource {
+	case "", common.PASSWORD_SOURCE_TTY, common.PASSWORD_SOURCE_STDIN:
+	case common.PASSWORD_SOURCE_FILE:
+		checkFile(problems, "PasswordFile", this.PasswordFile)
+	case common.PASSWORD_SOURCE_ENV:
+		if this.PasswordEnv == "" {
+			problems.add("PasswordEnv is empty")
+		}
+	default:
+		problems.add("PasswordSource %q is not one of tty, file, env or stdin", this.PasswordSource)
+	}
+	if this.LogLevel < 0 || this.LogLevel > log.MaxLevelLog {
+		problems.add("LogLevel %d is not between 0 and %d", this.LogLevel, log.MaxLevelLog)
+	}
+	if this.CheckpointPath == "" {
+		problems.add("CheckpointPath is empty")
+	}
+	if this.RetryJitter < 0 || this.RetryJitter > 1 {
+		problems.add("RetryJitter %v is not between 0 and 1", this.RetryJitter)
+	}
+	if this.RetryMaxDelay != 0 && this.RetryBaseDelay > this.RetryMaxDelay {
+		problems.add("RetryBaseDelay %d is greater than RetryMaxDelay %d", this.RetryBaseDelay, this.RetryMaxDelay)
+	}
+	if this.MonitorAddress != "" {
+		if _, _, err := net.SplitHostPort(this.MonitorAddress); err != nil {
+			problems.add("MonitorAddress %q: %s", this.MonitorAddress, err)
+		}
+	}
+	if this.AdminAddress != "" {
+		host, _, err := net.SplitHostPort(this.AdminAddress)
+		if err != nil {
+			problems.add("AdminAddress %q: %s", this.AdminAddress, err)
+		} else if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			problems.add("AdminAddress %q is not a loopback address", this.AdminAddress)
+		}
+		checkFile(problems, "AdminTokenFile", this.AdminTokenFile)
+	}
+	return problems.result()
+}
+
+func (this *Config) checkAccount(problems *ValidationError, name string, account *AccountConfig) {
+	if account.SignerAddress != "" {
+		checkURL(problems, name+" SignerAddress", account.SignerAddress, true, "http", "https")
+		checkFile(problems, name+" SignerTokenFile", account.SignerTokenFile)
+		if account.Address == "" {
+			problems.add("%s: Address is required with a remote signer", name)
+		}
+	} else {
+		checkFile(problems, name+" WalletFile", account.WalletFile)
+	}
+	selectors := 0
+	if account.Address != "" {
+		selectors++
+		if _, err := ocommon.AddressFromBase58(account.Address); err != nil {
+			problems.add("%s: invalid Address %s", name, account.Address)
+		}
+	}
+	if account.Label != "" {
+		selectors++
+	}
+	if account.Index != 0 {
+		selectors++
+		if account.Index < 0 {
+			problems.add("%s: Index %d is not positive", name, account.Index)
+		}
+	}
+	if selectors > 1 {
+		problems.add("%s: only one of Address, Label and Index may be set", name)
+	}
+}
+
+func (this *Config) checkMultiSig(problems *ValidationError, name string, multiSig *MultiSigConfig) {
+	if multiSig.M < 1 || multiSig.M > len(multiSig.PubKeys) {
+		problems.add("%s: M %d is not between 1 and the %d public keys", name, multiSig.M, len(multiSig.PubKeys))
+	}
+	for _, pubKey := range multiSig.PubKeys {
+		data, err := hex.DecodeString(pubKey)
+		if err == nil {
+			_, err = keypair.DeserializePublicKey(data)
+		}
+		if err != nil {
+			problems.add("%s: invalid public key %s", name, pubKey)
+		}
+	}
+	for _, account := range this.GetMultiSigAccounts(multiSig) {
+		this.checkAccount(problems, name+" account", account)
+		if account.SignerAddress != "" {
+			problems.add("%s: operator accounts must be local", name)
+		}
+	}
+	if len(multiSig.Accounts) < multiSig.M && multiSig.QueueDir == "" {
+		problems.add("%s: QueueDir is required with less than M accounts", name)
+	}
+}
+
+//checkURL check that address is an url of one of schemes, or is empty if not required
+func checkURL(problems *ValidationError, name, address string, required bool, schemes ...string) {
+	if address == "" {
+		if required {
+			problems.add("%s is empty", name)
+		}
+		return
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		problems.add("%s: invalid url %s", name, address)
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme && u.Host != "" {
+			return
+		}
+	}
+	problems.add("%s: %s is not a %s url", name, address, strings.Join(schemes, " or "))
+}
+
+func checkFile(problems *ValidationError, name, path string) {
+	if path == "" {
+		problems.add("%s is empty", name)
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		problems.add("%s: %s", name, err)
+	}
+}
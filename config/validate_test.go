@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidConfig() *Config {
+	cfg := NewConfig()
+	cfg.WalletFile = "../wallet.dat"
+	cfg.Chains = []*ChainConfig{
+		{ChainID: 0, JsonRpcAddress: "http://127.0.0.1:20336", WsAddress: "ws://127.0.0.1:20335", GasLimit: 20000},
+		{ChainID: 1, JsonRpcAddress: "http://127.0.0.1:30336", GasLimit: 20000},
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, newValidConfig().Validate())
+
+	cfg := newValidConfig()
+	cfg.WalletFile = "./missing.dat"
+	cfg.Chains[1].ChainID = 0
+	cfg.Chains[1].JsonRpcAddress = "127.0.0.1:30336"
+	cfg.Chains[1].GasLimit = 0
+	cfg.Routes = []*RouteConfig{{FromChainID: 0, ToChainID: 2}}
+	cfg.PasswordSource = "keyring"
+	cfg.MonitorAddress = "9101"
+	cfg.AdminAddress = "0.0.0.0:9102"
+	err := cfg.Validate()
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{
+		"chain 0 header account WalletFile: stat ./missing.dat: no such file or directory",
+		"chain 0 proof account WalletFile: stat ./missing.dat: no such file or directory",
+		"chain 0: duplicated chain id",
+		"chain 0 JsonRpcAddress: invalid url 127.0.0.1:30336",
+		"chain 0: GasLimit is 0",
+		"route 0->2: chain 2 is not configured",
+		`PasswordSource "keyring" is not one of tty, file, env or stdin`,
+		`MonitorAddress "9101": address 9101: missing port in address`,
+		`AdminAddress "0.0.0.0:9102" is not a loopback address`,
+		"AdminTokenFile is empty",
+	}, err.(*ValidationError).Problems)
+}
+
+func TestValidateRetryDelay(t *testing.T) {
+	cfg := newValidConfig()
+	cfg.RetryBaseDelay = 60000
+	cfg.RetryMaxDelay = 0
+	assert.Nil(t, cfg.Validate())
+
+	cfg.RetryMaxDelay = 1000
+	err := cfg.Validate()
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"RetryBaseDelay 60000 is greater than RetryMaxDelay 1000"}, err.(*ValidationError).Problems)
+}
+
+func TestValidateAccounts(t *testing.T) {
+	cfg := newValidConfig()
+	cfg.Chains[0].HeaderAccount = &AccountConfig{Address: "invalid", Label: "relayer"}
+	cfg.Chains[0].ProofAccount = &AccountConfig{SignerAddress: "http://127.0.0.1:20500"}
+	cfg.Chains[1].MultiSig = &MultiSigConfig{M: 2, PubKeys: []string{"00"}, Accounts: []*AccountConfig{{}}}
+	err := cfg.Validate()
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{
+		"chain 0 header account: invalid Address invalid",
+		"chain 0 header account: only one of Address, Label and Index may be set",
+		"chain 0 proof account SignerTokenFile is empty",
+		"chain 0 proof account: Address is required with a remote signer",
+		"chain 1 MultiSig: M 2 is not between 1 and the 1 public keys",
+		"chain 1 MultiSig: invalid public key 00",
+		"chain 1 MultiSig: QueueDir is required with less than M accounts",
+	}, err.(*ValidationError).Problems)
+}
+
+func TestLoadConfigStrict(t *testing.T) {
+	file, err := ioutil.TempFile("", "config")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("{\n  \"MainChainID\": 0,\n  \"GasLimt\": 20000\n}")
+	assert.Nil(t, err)
+	file.Close()
+
+	err = NewConfig().Init(file.Name())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `unknown field "GasLimt"`)
+
+	assert.Nil(t, ioutil.WriteFile(file.Name(), []byte("{\n  \"MainChainID\": \"0\"\n}"), 0600))
+	err = NewConfig().Init(file.Name())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 2 column")
+}
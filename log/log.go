@@ -30,6 +30,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -113,14 +114,14 @@ func NameLevel(name string) int {
 }
 
 type Logger struct {
-	level   int
+	level   int32
 	logger  *log.Logger
 	logFile *os.File
 }
 
 func New(out io.Writer, prefix string, flag, level int, file *os.File) *Logger {
 	return &Logger{
-		level:   level,
+		level:   int32(level),
 		logger:  log.New(out, prefix, flag),
 		logFile: file,
 	}
@@ -131,12 +132,17 @@ func (l *Logger) SetDebugLevel(level int) error {
 		return errors.New("Invalid Debug Level")
 	}
 
-	l.level = level
+	atomic.StoreInt32(&l.level, int32(level))
 	return nil
 }
 
+//getLevel return the level, which SetDebugLevel may change while logging
+func (l *Logger) getLevel() int {
+	return int(atomic.LoadInt32(&l.level))
+}
+
 func (l *Logger) Output(level int, a ...interface{}) error {
-	if level >= l.level {
+	if level >= l.getLevel() {
 		gid := GetGID()
 		gidStr := strconv.FormatUint(gid, 10)
 
@@ -149,7 +155,7 @@ func (l *Logger) Output(level int, a ...interface{}) error {
 }
 
 func (l *Logger) Outputf(level int, format string, v ...interface{}) error {
-	if level >= l.level {
+	if level >= l.getLevel() {
 		gid := GetGID()
 		v = append([]interface{}{LevelName(level), "GID",
 			gid}, v...)
@@ -208,7 +214,7 @@ func (l *Logger) Fatalf(format string, a ...interface{}) {
 }
 
 func Trace(a ...interface{}) {
-	if TraceLog < Log.level {
+	if TraceLog < Log.getLevel() {
 		return
 	}
 
@@ -228,7 +234,7 @@ func Trace(a ...interface{}) {
 }
 
 func Tracef(format string, a ...interface{}) {
-	if TraceLog < Log.level {
+	if TraceLog < Log.getLevel() {
 		return
 	}
 
@@ -248,7 +254,7 @@ func Tracef(format string, a ...interface{}) {
 }
 
 func Debug(a ...interface{}) {
-	if DebugLog < Log.level {
+	if DebugLog < Log.getLevel() {
 		return
 	}
 
@@ -264,7 +270,7 @@ func Debug(a ...interface{}) {
 }
 
 func Debugf(format string, a ...interface{}) {
-	if DebugLog < Log.level {
+	if DebugLog < Log.getLevel() {
 		return
 	}
 
@@ -18,18 +18,29 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/ontio/crossChainClient/checkpoint"
 	"github.com/ontio/crossChainClient/cmd"
 	"github.com/ontio/crossChainClient/common"
 	"github.com/ontio/crossChainClient/config"
 	"github.com/ontio/crossChainClient/log"
 	"github.com/ontio/crossChainClient/service"
+	"github.com/ontio/crossChainClient/signer"
+	"github.com/ontio/ontology-crypto/keypair"
 	sdk "github.com/ontio/ontology-go-sdk"
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/urfave/cli"
 )
 
@@ -38,11 +49,84 @@ func setupApp() *cli.App {
 	app.Usage = "Relayer cli"
 	app.Action = startSync
 	app.Copyright = "Copyright in 2018 The Ontology Authors"
-	app.Flags = []cli.Flag{
-		cmd.LogLevelFlag,
+	app.Description = "Every config field can be overridden by a CCC_* environment variable and a flag, " +
+		"with the precedence flags > environment > config file > defaults"
+	app.Flags = append([]cli.Flag{
 		cmd.ConfigPathFlag,
+		cmd.ConfigFormatFlag,
+	}, cmd.ConfigFlags()...)
+	app.Commands = []cli.Command{
+		{
+			Name:   "signer",
+			Usage:  "Serve the listed accounts of the wallet to remote relayers",
+			Action: startSigner,
+			Flags: []cli.Flag{
+				cmd.SignerListenFlag,
+				cmd.SignerAccountFlag,
+				cmd.SignerTokenFileFlag,
+			},
+		},
+		{
+			Name:   "multisig-sign",
+			Usage:  "Co-sign with the wallet account the multi-signature txs of a signing queue",
+			Action: signMultiSigQueue,
+			Flags: []cli.Flag{
+				cmd.QueueDirFlag,
+				cmd.WatchFlag,
+			},
+		},
+		{
+			Name:   "relay",
+			Usage:  "Relay the requests created by a block once, without starting the relay loops",
+			Action: relayRequest,
+			Flags: []cli.Flag{
+				cmd.FromChainFlag,
+				cmd.ToChainFlag,
+				cmd.HeightFlag,
+				cmd.RequestIDFlag,
+			},
+		},
+		{
+			Name:   "sync-header",
+			Usage:  "Sync a range of headers of a chain to another one, to bootstrap a side chain",
+			Action: syncHeaders,
+			Flags: []cli.Flag{
+				cmd.FromChainFlag,
+				cmd.ToChainFlag,
+				cmd.FromHeightFlag,
+				cmd.ToHeightFlag,
+				cmd.KeyOnlyFlag,
+			},
+		},
+		{
+			Name:   "status",
+			Usage:  "Show the cross chain sync state of every route, read from the chains",
+			Action: showStatus,
+			Flags: []cli.Flag{
+				cmd.StatusBlocksFlag,
+				cmd.JsonFlag,
+			},
+		},
+		{
+			Name:  "config",
+			Usage: "Check the config file",
+			Subcommands: []cli.Command{
+				{
+					Name:   "validate",
+					Usage:  "List every problem of the config file, exit with an error if any",
+					Action: validateConfig,
+					Flags: []cli.Flag{
+						cmd.LiveFlag,
+					},
+				},
+				{
+					Name:   "show",
+					Usage:  "Print the effective config, merged from the defaults, file, environment and flags",
+					Action: showConfig,
+				},
+			},
+		},
 	}
-	app.Commands = []cli.Command{}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		return nil
@@ -57,42 +141,531 @@ func main() {
 	}
 }
 
-func startSync(ctx *cli.Context) {
-	logLevel := ctx.GlobalInt(cmd.GetFlagName(cmd.LogLevelFlag))
-	log.InitLog(logLevel, log.PATH, log.Stdout)
-	configPath := ctx.String(cmd.GetFlagName(cmd.ConfigPathFlag))
-	err := config.DefConfig.Init(configPath)
+func startSync(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	if err := config.DefConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid config, run config validate for details:%s", err)
+	}
+
+	var adminToken string
+	if config.DefConfig.AdminAddress != "" {
+		token, err := signer.ReadToken(config.DefConfig.AdminTokenFile)
+		if err != nil {
+			return fmt.Errorf("read admin token error:%s", err)
+		}
+		adminToken = token
+	}
+	//serve the liveness while waiting for the wallet password
+	health := service.NewHealthHandler()
+	monitor := startServer("monitor", config.DefConfig.MonitorAddress, newMonitorHandler(health))
+	if monitor != nil {
+		defer monitor.Close()
+	}
+	syncService, store, err := newSyncService(ctx)
 	if err != nil {
-		fmt.Println("DefConfig.Init error:", err)
-		return
+		return err
 	}
+	syncService.Run(context.Background())
+	health.SetService(syncService)
+	admin := startServer("admin", config.DefConfig.AdminAddress, service.NewAdminServer(syncService, adminToken))
 
-	mainSdk := sdk.NewOntologySdk()
-	mainSdk.NewRpcClient().SetAddress(config.DefConfig.MainJsonRpcAddress)
-	sideSdk := sdk.NewOntologySdk()
-	sideSdk.NewRpcClient().SetAddress(config.DefConfig.SideJsonRpcAddress)
-	account, ok := common.GetAccountByPassword(mainSdk, config.DefConfig.WalletFile)
-	if !ok {
-		fmt.Println("common.GetAccountByPassword error")
-		return
+	waitToExit(func() {
+		reloadSync(ctx, syncService)
+	})
+	if admin != nil {
+		admin.Close()
+	}
+	//a relay loop still running may write to the store, leave it to the exit
+	if stopSync(syncService) {
+		store.Close()
 	}
+	return nil
+}
 
-	syncService := service.NewSyncService(account, mainSdk, sideSdk)
-	syncService.Run()
+//newClients connect to every chain of config.DefConfig
+func newClients() map[uint64]service.ChainClient {
+	clients := make(map[uint64]service.ChainClient)
+	for _, chain := range config.DefConfig.GetChains() {
+		ontSdk := sdk.NewOntologySdk()
+		ontSdk.NewRpcClient().SetAddress(chain.JsonRpcAddress)
+		clients[chain.ChainID] = service.NewSdkClient(ontSdk)
+	}
+	return clients
+}
+
+//newSyncService connect to the chains of config.DefConfig, unlock their accounts and open the
+//checkpoint store, which the caller must close
+func newSyncService(ctx *cli.Context) (*service.SyncService, *checkpoint.Store, error) {
+	accounts, err := loadAccounts(common.NewAccountLoader(sdk.NewOntologySdk(), getPasswordSource(ctx)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loadAccounts error:%s", err)
+	}
+	store, err := checkpoint.NewStore(config.DefConfig.CheckpointPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("checkpoint.NewStore error:%s", err)
+	}
+	syncService, err := service.NewSyncService(newClients(), accounts, store)
+	if err != nil {
+		store.Close()
+		return nil, nil, fmt.Errorf("service.NewSyncService error:%s", err)
+	}
+	return syncService, store, nil
+}
 
-	waitToExit()
+//newMonitorHandler serve the metrics and the health checks
+func newMonitorHandler(health *service.HealthHandler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	health.Register(mux)
+	return mux
 }
 
-func waitToExit() {
-	exit := make(chan bool, 0)
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+//startServer serve handler on address in the background, it return nil if address is empty
+func startServer(name, address string, handler http.Handler) *http.Server {
+	if address == "" {
+		return nil
+	}
+	server := &http.Server{Addr: address, Handler: handler}
 	go func() {
-		for sig := range sc {
-			log.Infof("Ontology received exit signal:%v.", sig.String())
-			close(exit)
-			break
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("%s http.ListenAndServe error:%s", name, err)
+		}
+	}()
+	log.Infof("%s listening on %s", name, address)
+	return server
+}
+
+//initConfig init config.DefConfig and log from the global flags
+func initConfig(ctx *cli.Context) bool {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	config.DefConfig = cfg
+	log.InitLog(cfg.LogLevel, log.PATH, log.Stdout)
+	return true
+}
+
+//loadConfig read the config file and apply the environment and flag overrides
+func loadConfig(ctx *cli.Context) (*config.Config, error) {
+	cfg := config.NewConfig()
+	configPath := ctx.GlobalString(cmd.GetFlagName(cmd.ConfigPathFlag))
+	configFormat := ctx.GlobalString(cmd.GetFlagName(cmd.ConfigFormatFlag))
+	err := cfg.InitWithFormat(configPath, configFormat)
+	if err != nil {
+		return nil, fmt.Errorf("config InitWithFormat error:%s", err)
+	}
+	err = cfg.Override(func(field *config.ConfigField) (string, bool) {
+		return os.LookupEnv(field.Env)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("environment override error:%s", err)
+	}
+	err = cfg.Override(func(field *config.ConfigField) (string, bool) {
+		return ctx.GlobalString(field.Flag), ctx.GlobalIsSet(field.Flag)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("flag override error:%s", err)
+	}
+	return cfg, nil
+}
+
+//validateConfig print the problems of the config and fail if there are any
+func validateConfig(ctx *cli.Context) error {
+	configPath := ctx.GlobalString(cmd.GetFlagName(cmd.ConfigPathFlag))
+	if !initConfig(ctx) {
+		return fmt.Errorf("config %s is invalid", configPath)
+	}
+	valid := true
+	if err := config.DefConfig.Validate(); err != nil {
+		fmt.Println(err)
+		valid = false
+	}
+	if ctx.Bool(cmd.GetFlagName(cmd.LiveFlag)) {
+		if err := config.DefConfig.CheckLive(); err != nil {
+			fmt.Println(err)
+			valid = false
+		}
+	}
+	if !valid {
+		return fmt.Errorf("config %s is invalid", configPath)
+	}
+	fmt.Printf("config %s is valid\n", configPath)
+	return nil
+}
+
+//showConfig print the effective config with the url passwords redacted
+func showConfig(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	redacted, err := config.DefConfig.Redacted()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(redacted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent error:%s", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func getPasswordSource(ctx *cli.Context) *common.PasswordSource {
+	pwdSource := &common.PasswordSource{
+		Type: config.DefConfig.PasswordSource,
+		File: config.DefConfig.PasswordFile,
+		Env:  config.DefConfig.PasswordEnv,
+	}
+	//a password file given on the command line implies the file source
+	if ctx.GlobalIsSet(config.GetConfigField("PasswordFile").Flag) {
+		pwdSource.Type = common.PASSWORD_SOURCE_FILE
+	}
+	return pwdSource
+}
+
+//loadAccounts create the header sync and proof signers of every chain
+func loadAccounts(loader *common.AccountLoader) (map[uint64]*service.ChainAccounts, error) {
+	defer loader.Close()
+	accounts := make(map[uint64]*service.ChainAccounts)
+	load := func(account *config.AccountConfig) (service.Signer, error) {
+		if account.SignerAddress != "" {
+			token, err := signer.ReadToken(account.SignerTokenFile)
+			if err != nil {
+				return nil, err
+			}
+			return signer.NewRemoteSigner(account.SignerAddress, token, account.Address)
+		}
+		acct, err := loader.Load(account.WalletFile, &common.AccountSelector{
+			Address: account.Address,
+			Label:   account.Label,
+			Index:   account.Index,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return signer.NewAccountSigner(acct), nil
+	}
+	for _, chain := range config.DefConfig.GetChains() {
+		if chain.MultiSig != nil {
+			multiSigner, err := loadMultiSigner(loader, chain.MultiSig)
+			if err != nil {
+				return nil, fmt.Errorf("chain %d multi-signature error:%s", chain.ChainID, err)
+			}
+			address := multiSigner.Address()
+			log.Infof("chain %d %d of %d multi-signature account %s", chain.ChainID, chain.MultiSig.M,
+				len(chain.MultiSig.PubKeys), address.ToBase58())
+			accounts[chain.ChainID] = &service.ChainAccounts{Header: multiSigner, Proof: multiSigner}
+			continue
+		}
+		header, err := load(config.DefConfig.GetHeaderAccount(chain))
+		if err != nil {
+			return nil, fmt.Errorf("chain %d header account error:%s", chain.ChainID, err)
+		}
+		proof, err := load(config.DefConfig.GetProofAccount(chain))
+		if err != nil {
+			return nil, fmt.Errorf("chain %d proof account error:%s", chain.ChainID, err)
+		}
+		headerAddress, proofAddress := header.Address(), proof.Address()
+		log.Infof("chain %d header sync account %s, proof account %s", chain.ChainID,
+			headerAddress.ToBase58(), proofAddress.ToBase58())
+		accounts[chain.ChainID] = &service.ChainAccounts{Header: header, Proof: proof}
+	}
+	return accounts, nil
+}
+
+//loadAddresses create signers holding only the address of the header sync and proof accounts of
+//every chain, to read the chains without unlocking the wallet
+func loadAddresses(loader *common.AccountLoader) (map[uint64]*service.ChainAccounts, error) {
+	load := func(account *config.AccountConfig) (service.Signer, error) {
+		address := account.Address
+		if address == "" {
+			var err error
+			address, err = loader.Address(account.WalletFile, &common.AccountSelector{
+				Label: account.Label,
+				Index: account.Index,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		addr, err := ocommon.AddressFromBase58(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s error:%s", address, err)
+		}
+		return signer.NewAddressSigner(addr), nil
+	}
+	accounts := make(map[uint64]*service.ChainAccounts)
+	for _, chain := range config.DefConfig.GetChains() {
+		if chain.MultiSig != nil {
+			pubKeys, err := parsePubKeys(chain.MultiSig)
+			if err != nil {
+				return nil, fmt.Errorf("chain %d multi-signature error:%s", chain.ChainID, err)
+			}
+			address, err := signer.MultiSigAddress(chain.MultiSig.M, pubKeys)
+			if err != nil {
+				return nil, fmt.Errorf("chain %d multi-signature error:%s", chain.ChainID, err)
+			}
+			multiSigner := signer.NewAddressSigner(address)
+			accounts[chain.ChainID] = &service.ChainAccounts{Header: multiSigner, Proof: multiSigner}
+			continue
+		}
+		header, err := load(config.DefConfig.GetHeaderAccount(chain))
+		if err != nil {
+			return nil, fmt.Errorf("chain %d header account error:%s", chain.ChainID, err)
 		}
+		proof, err := load(config.DefConfig.GetProofAccount(chain))
+		if err != nil {
+			return nil, fmt.Errorf("chain %d proof account error:%s", chain.ChainID, err)
+		}
+		accounts[chain.ChainID] = &service.ChainAccounts{Header: header, Proof: proof}
+	}
+	return accounts, nil
+}
+
+//loadMultiSigner create the signer of a multi-signature account
+func loadMultiSigner(loader *common.AccountLoader, multiSig *config.MultiSigConfig) (*signer.MultiSigner, error) {
+	pubKeys, err := parsePubKeys(multiSig)
+	if err != nil {
+		return nil, err
+	}
+	operators := make([]*sdk.Account, 0, len(multiSig.Accounts))
+	for _, account := range config.DefConfig.GetMultiSigAccounts(multiSig) {
+		acct, err := loader.Load(account.WalletFile, &common.AccountSelector{
+			Address: account.Address,
+			Label:   account.Label,
+			Index:   account.Index,
+		})
+		if err != nil {
+			return nil, err
+		}
+		operators = append(operators, acct)
+	}
+	var queue *signer.SignQueue
+	if multiSig.QueueDir != "" {
+		queue, err = signer.NewSignQueue(multiSig.QueueDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	timeout := multiSig.QueueTimeout
+	if timeout == 0 {
+		timeout = config.DEFAULT_QUEUE_TIMEOUT
+	}
+	return signer.NewMultiSigner(multiSig.M, pubKeys, operators, queue, time.Duration(timeout)*time.Second)
+}
+
+//parsePubKeys decode the hex public keys of the operators of multiSig
+func parsePubKeys(multiSig *config.MultiSigConfig) ([]keypair.PublicKey, error) {
+	pubKeys := make([]keypair.PublicKey, 0, len(multiSig.PubKeys))
+	for _, pubKey := range multiSig.PubKeys {
+		data, err := hex.DecodeString(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("public key %s hex.DecodeString error:%s", pubKey, err)
+		}
+		key, err := keypair.DeserializePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("public key %s keypair.DeserializePublicKey error:%s", pubKey, err)
+		}
+		pubKeys = append(pubKeys, key)
+	}
+	return pubKeys, nil
+}
+
+//signMultiSigQueue add the signature of the relayer account to the relay txs waiting in a signing
+//queue and paid by a configured multi-signature address
+func signMultiSigQueue(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	dir := ctx.String(cmd.GetFlagName(cmd.QueueDirFlag))
+	if dir == "" {
+		return fmt.Errorf("missing --queue-dir")
+	}
+	payers, err := multiSigAddresses()
+	if err != nil {
+		return err
+	}
+	queue, err := signer.NewSignQueue(dir)
+	if err != nil {
+		return fmt.Errorf("signer.NewSignQueue error:%s", err)
+	}
+	loader := common.NewAccountLoader(sdk.NewOntologySdk(), getPasswordSource(ctx))
+	defer loader.Close()
+	account, err := loader.Load(config.DefConfig.WalletFile, &common.AccountSelector{
+		Address: config.DefConfig.AccountAddress,
+		Label:   config.DefConfig.AccountLabel,
+		Index:   config.DefConfig.AccountIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("loader.Load error:%s", err)
+	}
+	//the txs refused are reported once
+	refused := make(map[ocommon.Uint256]bool)
+	for {
+		txs, err := queue.Pending()
+		if err != nil {
+			log.Errorf("queue.Pending error:%s", err)
+		}
+		for _, tx := range txs {
+			txHash := tx.Hash()
+			if refused[txHash] || queue.HasSignature(txHash, account.PublicKey) {
+				continue
+			}
+			chainID, ok := payers[tx.Payer]
+			if !ok {
+				log.Warnf("refuse tx %s paid by %s, which is not a configured multi-signature address",
+					txHash.ToHexString(), tx.Payer.ToBase58())
+				refused[txHash] = true
+				continue
+			}
+			relay, err := signer.CheckRelayTx(tx)
+			if err != nil {
+				log.Warnf("refuse tx %s:%s", txHash.ToHexString(), err)
+				refused[txHash] = true
+				continue
+			}
+			fmt.Printf("sign tx %s: %s of contract %s on chain %d, paid by %s\n", txHash.ToHexString(),
+				relay.Method, relay.Contract.ToHexString(), chainID, tx.Payer.ToBase58())
+			err = queue.AddSignature(tx, account)
+			if err != nil {
+				log.Errorf("sign tx %s error:%s", txHash.ToHexString(), err)
+				continue
+			}
+			log.Infof("signed tx %s paid by %s", txHash.ToHexString(), tx.Payer.ToBase58())
+		}
+		if !ctx.Bool(cmd.GetFlagName(cmd.WatchFlag)) {
+			return nil
+		}
+		time.Sleep(signer.QUEUE_POLL_INTERVAL)
+	}
+}
+
+//multiSigAddresses return the chain of every configured multi-signature address
+func multiSigAddresses() (map[ocommon.Address]uint64, error) {
+	addresses := make(map[ocommon.Address]uint64)
+	for _, chain := range config.DefConfig.GetChains() {
+		if chain.MultiSig == nil {
+			continue
+		}
+		pubKeys, err := parsePubKeys(chain.MultiSig)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d multi-signature error:%s", chain.ChainID, err)
+		}
+		address, err := signer.MultiSigAddress(chain.MultiSig.M, pubKeys)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d multi-signature error:%s", chain.ChainID, err)
+		}
+		addresses[address] = chain.ChainID
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no chain has a MultiSig config")
+	}
+	return addresses, nil
+}
+
+//startSigner serve every account of the wallet to the relayers presenting the API token
+func startSigner(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	accounts, err := loadServedAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	listen := ctx.String(cmd.GetFlagName(cmd.SignerListenFlag))
+	log.Infof("signer serving %d accounts on %s", len(accounts), listen)
+	err = http.ListenAndServe(listen, signer.NewServer(accounts))
+	if err != nil {
+		return fmt.Errorf("signer http.ListenAndServe error:%s", err)
+	}
+	return nil
+}
+
+//loadServedAccounts unlock the wallet accounts listed by --account, each with its own token or the
+//one of --token-file
+func loadServedAccounts(ctx *cli.Context) ([]*signer.ServedAccount, error) {
+	accountFlag := cmd.GetFlagName(cmd.SignerAccountFlag)
+	specs := ctx.StringSlice(accountFlag)
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("--%s is required", accountFlag)
+	}
+	loader := common.NewAccountLoader(sdk.NewOntologySdk(), getPasswordSource(ctx))
+	defer loader.Close()
+	accounts := make([]*signer.ServedAccount, 0, len(specs))
+	for _, spec := range specs {
+		address, tokenFile := spec, ctx.String(cmd.GetFlagName(cmd.SignerTokenFileFlag))
+		if i := strings.Index(spec, "="); i >= 0 {
+			address, tokenFile = spec[:i], spec[i+1:]
+		}
+		token, err := signer.ReadToken(tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("account %s token error:%s", address, err)
+		}
+		account, err := loader.Load(config.DefConfig.WalletFile, &common.AccountSelector{Address: address})
+		if err != nil {
+			return nil, fmt.Errorf("loader.Load error:%s", err)
+		}
+		accounts = append(accounts, &signer.ServedAccount{Account: account, Token: token})
+	}
+	return accounts, nil
+}
+
+//reloadSync reload the config and apply its safe to change fields to syncService
+func reloadSync(ctx *cli.Context, syncService *service.SyncService) {
+	cfg, err := loadConfig(ctx)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		log.Errorf("reload config error, keep the current config:%s", err)
+		return
+	}
+	applied, rejected := syncService.Reload(cfg)
+	for _, change := range rejected {
+		log.Warnf("reload config: ignore %s, restart the relayer to apply it", change)
+	}
+	for _, change := range applied {
+		log.Infof("reload config: applied %s", change)
+	}
+	if len(applied) == 0 {
+		log.Infof("reload config: nothing to apply")
+	}
+}
+
+//stopSync stop syncService, waiting at most ShutdownTimeout. It return whether it stopped
+func stopSync(syncService *service.SyncService) bool {
+	timeout := syncService.GetShutdownTimeout()
+	done := make(chan struct{})
+	go func() {
+		syncService.Stop()
+		close(done)
 	}()
-	<-exit
+	select {
+	case <-done:
+		log.Infof("relayer stopped")
+		return true
+	case <-time.After(timeout):
+		log.Warnf("relayer not stopped after %s, exit anyway", timeout)
+		return false
+	}
+}
+
+//waitToExit wait for an exit signal, calling reload on each SIGHUP
+func waitToExit(reload func()) {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sc {
+		if sig == syscall.SIGHUP {
+			log.Infof("Ontology received reload signal:%v.", sig.String())
+			reload()
+			continue
+		}
+		log.Infof("Ontology received exit signal:%v.", sig.String())
+		return
+	}
 }
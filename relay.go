@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ontio/crossChainClient/cmd"
+	"github.com/ontio/crossChainClient/config"
+	"github.com/ontio/crossChainClient/service"
+	"github.com/urfave/cli"
+)
+
+//relayRequest relay once the requests created by a block, without starting the relay loops
+func relayRequest(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	if err := config.DefConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid config, run config validate for details:%s", err)
+	}
+	height, ok, err := getHeight(ctx, cmd.HeightFlag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("--%s is required", cmd.GetFlagName(cmd.HeightFlag))
+	}
+	var requestIDs []uint64
+	if requestIDFlag := cmd.GetFlagName(cmd.RequestIDFlag); ctx.IsSet(requestIDFlag) {
+		requestIDs = []uint64{ctx.Uint64(requestIDFlag)}
+	}
+
+	syncService, store, err := newSyncService(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	route, err := getRoute(syncService, ctx.String(cmd.GetFlagName(cmd.FromChainFlag)),
+		ctx.String(cmd.GetFlagName(cmd.ToChainFlag)))
+	if err != nil {
+		return err
+	}
+	relayed, err := syncService.RelayRequests(context.Background(), route, height, requestIDs)
+	for _, tx := range route.Status().LastTxs {
+		fmt.Printf("%s %s %s\n", tx.Method, tx.TxHash, tx.Result)
+	}
+	if err != nil {
+		return fmt.Errorf("[%s] relay block %d error:%s", route.Name, height, err)
+	}
+	fmt.Printf("[%s] relayed requests %v of block %d\n", route.Name, relayed, height)
+	return nil
+}
+
+//syncHeaders sync a range of headers of a chain to another one, reporting the ones already synced
+func syncHeaders(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	if err := config.DefConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid config, run config validate for details:%s", err)
+	}
+	start, ok, err := getHeight(ctx, cmd.FromHeightFlag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("--%s is required", cmd.GetFlagName(cmd.FromHeightFlag))
+	}
+	end, ok, err := getHeight(ctx, cmd.ToHeightFlag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		end = start
+	}
+	if end < start {
+		return fmt.Errorf("--%s %d is lower than --%s %d", cmd.GetFlagName(cmd.ToHeightFlag), end,
+			cmd.GetFlagName(cmd.FromHeightFlag), start)
+	}
+
+	syncService, store, err := newSyncService(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	route, err := getRoute(syncService, ctx.String(cmd.GetFlagName(cmd.FromChainFlag)),
+		ctx.String(cmd.GetFlagName(cmd.ToChainFlag)))
+	if err != nil {
+		return err
+	}
+	result, err := syncService.SyncHeaders(context.Background(), route, start, end,
+		ctx.Bool(cmd.GetFlagName(cmd.KeyOnlyFlag)))
+	fmt.Printf("[%s] already synced headers: %s\n", route.Name, formatHeights(result.Present))
+	fmt.Printf("[%s] synced headers: %s\n", route.Name, formatHeights(result.Synced))
+	if err != nil {
+		return fmt.Errorf("[%s] sync headers %d to %d error:%s", route.Name, start, end, err)
+	}
+	return nil
+}
+
+//getHeight return the block height set by flag, false if it is not set
+func getHeight(ctx *cli.Context, flag cli.Flag) (uint32, bool, error) {
+	name := cmd.GetFlagName(flag)
+	if !ctx.IsSet(name) {
+		return 0, false, nil
+	}
+	if ctx.Uint(name) > math.MaxUint32 {
+		return 0, false, fmt.Errorf("invalid --%s %d", name, ctx.Uint(name))
+	}
+	return uint32(ctx.Uint(name)), true, nil
+}
+
+//formatHeights format sorted heights as ranges, like 1-3, 7
+func formatHeights(heights []uint32) string {
+	if len(heights) == 0 {
+		return "none"
+	}
+	ranges := make([]string, 0)
+	for i := 0; i < len(heights); {
+		j := i
+		for j+1 < len(heights) && heights[j+1] == heights[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("%d", heights[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", heights[i], heights[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
+
+//getRoute return the route of syncService between the chains named from and to, see findChains
+func getRoute(syncService *service.SyncService, from, to string) (*service.Route, error) {
+	fromChain, toChain, err := findChains(from, to)
+	if err != nil {
+		return nil, err
+	}
+	route := syncService.GetRoute(fromChain.ChainID, toChain.ChainID)
+	if route == nil {
+		return nil, fmt.Errorf("no route from chain %d to chain %d", fromChain.ChainID, toChain.ChainID)
+	}
+	return route, nil
+}
+
+//findChains return the chains of config.DefConfig named from and to, see config.Config.FindChain. One
+//of them may be empty for the side chain if the other one is the main chain, and the main chain otherwise
+func findChains(from, to string) (*config.ChainConfig, *config.ChainConfig, error) {
+	if from == "" && to == "" {
+		return nil, nil, fmt.Errorf("no chain given, set --%s or --%s",
+			cmd.GetFlagName(cmd.FromChainFlag), cmd.GetFlagName(cmd.ToChainFlag))
+	}
+	var fromChain, toChain *config.ChainConfig
+	var err error
+	if from != "" {
+		fromChain, err = config.DefConfig.FindChain(from)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if to != "" {
+		toChain, err = config.DefConfig.FindChain(to)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if fromChain == nil {
+		fromChain, err = config.DefConfig.FindChain(otherChain(toChain))
+	}
+	if toChain == nil {
+		toChain, err = config.DefConfig.FindChain(otherChain(fromChain))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return fromChain, toChain, nil
+}
+
+//otherChain return the name of the default chain to relay with chain
+func otherChain(chain *config.ChainConfig) string {
+	if chain.ChainID == config.DefConfig.MainChainID {
+		return "side"
+	}
+	return "main"
+}
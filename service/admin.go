@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ontio/crossChainClient/log"
+)
+
+const (
+	ADMIN_STATUS_PATH    = "/v1/status"
+	ADMIN_PAUSE_PATH     = "/v1/pause"
+	ADMIN_RESUME_PATH    = "/v1/resume"
+	ADMIN_RELAY_PATH     = "/v1/relay"
+	ADMIN_SKIP_PATH      = "/v1/skip"
+	ADMIN_LOG_LEVEL_PATH = "/v1/loglevel"
+	//max size of an admin request body
+	MAX_ADMIN_REQUEST_SIZE = 1 << 16
+)
+
+//StateError is an admin operation not allowed in the current state of a route
+type StateError string
+
+func (this StateError) Error() string {
+	return string(this)
+}
+
+//Status return the state of every route
+func (this *SyncService) Status() []*RouteStatus {
+	statuses := make([]*RouteStatus, 0, len(this.routes))
+	for _, route := range this.routes {
+		statuses = append(statuses, route.Status())
+	}
+	return statuses
+}
+
+//Pause stop relaying the blocks of route once the block in flight is relayed
+func (this *SyncService) Pause(route *Route) {
+	route.setPaused(true)
+	log.Warnf("[%s] paused", route.Name)
+}
+
+func (this *SyncService) Resume(route *Route) {
+	route.setPaused(false)
+	log.Infof("[%s] resumed", route.Name)
+}
+
+//Rerelay relay again the requests created by block height of route.From for route.To, or only the ones
+//in requestIDs if it is not empty, even if the checkpoint store marks them relayed. The block must
+//already be relayed. It return the ids of the requests relayed
+func (this *SyncService) Rerelay(ctx context.Context, route *Route, height uint32, requestIDs []uint64) ([]uint64, error) {
+	if next := route.getSyncHeight(); height >= next {
+		return nil, StateError(fmt.Sprintf("block %d is not relayed yet, next height %d", height, next))
+	}
+	log.Infof("[%s] relay block %d again, requests %v", route.Name, height, requestIDs)
+	return this.relayRequests(ctx, route, height, true, requestIDs)
+}
+
+//Skip mark block height of route relayed without relaying its requests, to get past a block which
+//cannot be relayed. It must be the next height of route
+func (this *SyncService) Skip(route *Route, height uint32) error {
+	route.checkpointLock.Lock()
+	defer route.checkpointLock.Unlock()
+	if !route.advance(height) {
+		return StateError(fmt.Sprintf("block %d is not the next height %d", height, route.getSyncHeight()))
+	}
+	route.notify()
+	log.Warnf("[%s] block %d skipped", route.Name, height)
+	//the route moved past height anyway, the next relayed block checkpoints it if this write fails
+	err := this.store.PutHeight(route.From.ChainID, route.To.ChainID, height)
+	if err != nil {
+		return fmt.Errorf("this.store.PutHeight error:%s", err)
+	}
+	return nil
+}
+
+//SetLogLevel change the log level of the running service as a reload of the config would
+func (this *SyncService) SetLogLevel(level int) error {
+	if level < 0 || level > log.MaxLevelLog {
+		return fmt.Errorf("log level %d is not between 0 and %d", level, log.MaxLevelLog)
+	}
+	//copy and apply in the same critical section, so that a concurrent reload is not lost
+	this.reloadLock.Lock()
+	defer this.reloadLock.Unlock()
+	cfg := *this.GetConfig()
+	cfg.LogLevel = level
+	this.reloadLocked(&cfg)
+	return nil
+}
+
+//AdminRequest is the body of the admin api POST requests, each using the fields it needs
+type AdminRequest struct {
+	FromChainID uint64
+	ToChainID   uint64
+	Height      uint32
+	RequestIDs  []uint64 `json:",omitempty"`
+	LogLevel    int
+}
+
+type AdminResponse struct {
+	Routes  []*RouteStatus `json:",omitempty"`
+	Relayed []uint64       `json:",omitempty"`
+	Error   string         `json:",omitempty"`
+}
+
+//AdminServer is the admin api of a running service, serving the clients presenting token:
+//
+//	GET  /v1/status                                  state of every route
+//	POST /v1/pause {FromChainID, ToChainID}          pause a route
+//	POST /v1/resume {FromChainID, ToChainID}         resume a route
+//	POST /v1/relay {FromChainID, ToChainID, Height, RequestIDs}
+//	                                                 relay the requests of a relayed block again
+//	POST /v1/skip {FromChainID, ToChainID, Height}   skip the next block of a route
+//	POST /v1/loglevel {LogLevel}                     change the log level
+type AdminServer struct {
+	service *SyncService
+	token   string
+}
+
+func NewAdminServer(syncService *SyncService, token string) *AdminServer {
+	return &AdminServer{service: syncService, token: token}
+}
+
+func (this *AdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !this.authorized(r) {
+		writeAdminError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+		return
+	}
+	if r.URL.Path == ADMIN_STATUS_PATH && r.Method == http.MethodGet {
+		writeAdminJson(w, http.StatusOK, &AdminResponse{Routes: this.service.Status()})
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("%s %s not found", r.Method, r.URL.Path))
+		return
+	}
+	req := &AdminRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_ADMIN_REQUEST_SIZE))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		writeAdminError(w, http.StatusBadRequest, fmt.Errorf("decode request error:%s", err))
+		return
+	}
+	if r.URL.Path == ADMIN_LOG_LEVEL_PATH {
+		if err := this.service.SetLogLevel(req.LogLevel); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeAdminJson(w, http.StatusOK, &AdminResponse{})
+		return
+	}
+	route := this.service.GetRoute(req.FromChainID, req.ToChainID)
+	if route == nil {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("no route from chain %d to chain %d", req.FromChainID, req.ToChainID))
+		return
+	}
+	resp := &AdminResponse{}
+	var err error
+	switch r.URL.Path {
+	case ADMIN_PAUSE_PATH:
+		this.service.Pause(route)
+	case ADMIN_RESUME_PATH:
+		this.service.Resume(route)
+	case ADMIN_RELAY_PATH:
+		resp.Relayed, err = this.service.Rerelay(r.Context(), route, req.Height, req.RequestIDs)
+	case ADMIN_SKIP_PATH:
+		err = this.service.Skip(route, req.Height)
+	default:
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("%s %s not found", r.Method, r.URL.Path))
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(StateError); ok {
+			status = http.StatusConflict
+		}
+		resp.Error = err.Error()
+		writeAdminJson(w, status, resp)
+		return
+	}
+	resp.Routes = []*RouteStatus{route.Status()}
+	writeAdminJson(w, http.StatusOK, resp)
+}
+
+func (this *AdminServer) authorized(r *http.Request) bool {
+	expected := []byte("Bearer " + this.token)
+	return this.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) == 1
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	writeAdminJson(w, status, &AdminResponse{Error: err.Error()})
+}
+
+func writeAdminJson(w http.ResponseWriter, status int, resp *AdminResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Errorf("[admin] write response error:%s", err)
+	}
+}
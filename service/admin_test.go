@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAdminToken = "admin-token"
+
+func newTestAdminServer(syncService *SyncService) *httptest.Server {
+	return httptest.NewServer(NewAdminServer(syncService, testAdminToken))
+}
+
+func adminCall(t *testing.T, server *httptest.Server, token, method, path string, req *AdminRequest) (int, *AdminResponse) {
+	var body bytes.Buffer
+	if req != nil {
+		assert.Nil(t, json.NewEncoder(&body).Encode(req))
+	}
+	httpReq, err := http.NewRequest(method, server.URL+path, &body)
+	assert.Nil(t, err)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	assert.Nil(t, err)
+	defer httpResp.Body.Close()
+	resp := &AdminResponse{}
+	assert.Nil(t, json.NewDecoder(httpResp.Body).Decode(resp))
+	return httpResp.StatusCode, resp
+}
+
+func TestAdminServer(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	server := newTestAdminServer(syncService)
+	defer server.Close()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+
+	status, _ := adminCall(t, server, "wrong", http.MethodGet, ADMIN_STATUS_PATH, nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, resp := adminCall(t, server, testAdminToken, http.MethodGet, ADMIN_STATUS_PATH, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 2, len(resp.Routes))
+	status, _ = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_PAUSE_PATH,
+		&AdminRequest{FromChainID: testMainChainID, ToChainID: 5})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	//the request is marked relayed but was never processed by the side chain
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	assert.Nil(t, syncService.store.MarkRelayed(testMainChainID, testSideChainID, 1))
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 0, len(sideChain.Proofs()))
+	relay := &AdminRequest{FromChainID: testMainChainID, ToChainID: testSideChainID, Height: height, RequestIDs: []uint64{1}}
+	status, resp = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_RELAY_PATH, relay)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, "block 1 is not relayed yet, next height 0", resp.Error)
+
+	route.setSyncHeight(height + 1)
+	status, resp = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_RELAY_PATH, relay)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []uint64{1}, resp.Relayed)
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+	txs := resp.Routes[0].LastTxs
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, cross_chain.PROCESS_CROSS_CHAIN_TX, txs[1].Method)
+	assert.Equal(t, "success", txs[1].Result)
+	relay.RequestIDs = []uint64{2}
+	status, resp = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_RELAY_PATH, relay)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, "1 requests not found in block 1", resp.Error)
+
+	skip := &AdminRequest{FromChainID: testMainChainID, ToChainID: testSideChainID, Height: height}
+	status, _ = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_SKIP_PATH, skip)
+	assert.Equal(t, http.StatusConflict, status)
+	skip.Height = height + 1
+	status, resp = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_SKIP_PATH, skip)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, height+2, resp.Routes[0].NextHeight)
+	checkpointed, _, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, height+1, checkpointed)
+
+	level := syncService.GetConfig().LogLevel
+	defer syncService.SetLogLevel(level)
+	status, _ = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_LOG_LEVEL_PATH, &AdminRequest{LogLevel: 9})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_LOG_LEVEL_PATH, &AdminRequest{LogLevel: 1})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, syncService.GetConfig().LogLevel)
+}
+
+func TestAdminPauseResume(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	server := newTestAdminServer(syncService)
+	defer server.Close()
+	route := &AdminRequest{FromChainID: testMainChainID, ToChainID: testSideChainID}
+
+	status, resp := adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_PAUSE_PATH, route)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, resp.Routes[0].Paused)
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	sideChain.AddBlock(false, [2]uint64{testMainChainID, 2})
+	sideChain.AddBlock(false)
+	syncService.Run(context.Background())
+	defer syncService.Stop()
+
+	waitFor(t, func() bool { return len(mainChain.Proofs()) == 1 })
+	_, resp = adminCall(t, server, testAdminToken, http.MethodGet, ADMIN_STATUS_PATH, nil)
+	assert.Equal(t, 0, len(sideChain.Proofs()))
+	assert.Equal(t, uint32(0), resp.Routes[0].NextHeight)
+	assert.Equal(t, uint32(2), resp.Routes[0].Lag)
+
+	status, resp = adminCall(t, server, testAdminToken, http.MethodPost, ADMIN_RESUME_PATH, route)
+	assert.Equal(t, http.StatusOK, status)
+	assert.False(t, resp.Routes[0].Paused)
+	waitFor(t, func() bool { return len(sideChain.Proofs()) == 1 })
+}
+
+//waitFor wait until cond is true, failing t after 5 seconds
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSkipWhileRelaying(t *testing.T) {
+	syncService, _, _, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	route.setSyncHeight(5)
+
+	//blocks 5 and 6 are skipped while the relay loop relays block 5
+	assert.Nil(t, syncService.Skip(route, 5))
+	assert.Nil(t, syncService.Skip(route, 6))
+	err := syncService.Skip(route, 6)
+	assert.IsType(t, StateError(""), err)
+	assert.Nil(t, syncService.checkpoint(route, 5))
+
+	checkpointed, _, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(6), checkpointed)
+	assert.Equal(t, uint32(7), route.getSyncHeight())
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ontio/crossChainClient/log"
+	sdkclient "github.com/ontio/ontology-go-sdk/client"
+)
+
+const (
+	//interval of websocket heartbeats, the node drops idle subscribers
+	WS_HEARTBEAT_INTERVAL = 30 * time.Second
+	//a subscription is considered dead if nothing is received for this long
+	WS_READ_TIMEOUT = 2 * WS_HEARTBEAT_INTERVAL
+	//how long to poll before trying to subscribe again after the websocket failed
+	WS_RETRY_INTERVAL = time.Minute
+)
+
+//BlockSource notify relay workers of the current block height of a chain
+type BlockSource interface {
+	//Heights return a channel receiving the current height each time the chain grows, closed once
+	//the source context is done. Only the latest height is buffered, a slow reader skips
+	//intermediate notifications
+	Heights() <-chan uint32
+}
+
+//blockFeed is a BlockSource subscribing to new blocks on the Ontology websocket api, falling back
+//to polling GetCurrentBlockHeight every interval() while the websocket is not configured or unavailable
+type blockFeed struct {
+	name      string
+	client    ChainClient
+	wsAddress string
+	interval  func() time.Duration
+	heights   chan uint32
+	last      uint32
+	published bool
+	ctx       context.Context
+}
+
+//NewBlockSource start feeding the heights of the chain behind client until ctx is done. name is
+//used in logs, wsAddress may be empty to always poll and interval is read before each poll so that
+//it can change
+func NewBlockSource(ctx context.Context, name string, client ChainClient, wsAddress string, interval func() time.Duration) BlockSource {
+	feed := &blockFeed{
+		name:      name,
+		client:    client,
+		wsAddress: wsAddress,
+		interval:  interval,
+		heights:   make(chan uint32, 1),
+		ctx:       ctx,
+	}
+	go feed.run()
+	return feed
+}
+
+func (this *blockFeed) Heights() <-chan uint32 {
+	return this.heights
+}
+
+func (this *blockFeed) run() {
+	defer close(this.heights)
+	for {
+		retry := time.Duration(0)
+		if this.wsAddress != "" {
+			err := this.subscribe()
+			if this.ctx.Err() != nil {
+				return
+			}
+			log.Warnf("[%s] block subscription on %s error:%s, fall back to polling", this.name, this.wsAddress, err)
+			retry = WS_RETRY_INTERVAL
+		}
+		if !this.poll(retry) {
+			return
+		}
+	}
+}
+
+//poll publish the current height every interval, until the context is done or, if retry is not zero,
+//until retry elapsed. It return false once the context is done
+func (this *blockFeed) poll(retry time.Duration) bool {
+	start := time.Now()
+	for {
+		this.fetchHeight()
+		if !sleep(this.ctx, this.interval()) {
+			return false
+		}
+		if retry != 0 && time.Since(start) >= retry {
+			return true
+		}
+	}
+}
+
+func (this *blockFeed) fetchHeight() {
+	height, err := this.client.GetCurrentBlockHeight()
+	if err != nil {
+		countRpcError("GetCurrentBlockHeight")
+		log.Errorf("[%s] GetCurrentBlockHeight error:%s", this.name, err)
+		return
+	}
+	this.publish(height)
+}
+
+//publish hand height to the reader, replacing any height it has not consumed yet
+func (this *blockFeed) publish(height uint32) {
+	if this.published && height <= this.last {
+		return
+	}
+	this.last = height
+	this.published = true
+	publishLatest(this.heights, height)
+}
+
+//publishLatest send height on a channel of capacity 1 written by a single goroutine, replacing the
+//height buffered if the reader has not consumed it yet
+func publishLatest(heights chan uint32, height uint32) {
+	select {
+	case heights <- height:
+	default:
+		select {
+		case <-heights:
+		default:
+		}
+		heights <- height
+	}
+}
+
+//subscribe follow new blocks over the websocket until the connection fails or the context is done
+func (this *blockFeed) subscribe() error {
+	conn, _, err := websocket.DefaultDialer.Dial(this.wsAddress, nil)
+	if err != nil {
+		return fmt.Errorf("dial error:%s", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	var writeLock sync.Mutex
+	send := func(action string, params map[string]interface{}) error {
+		req := map[string]interface{}{
+			"Action":  action,
+			"Version": sdkclient.WS_VERSION,
+		}
+		for k, v := range params {
+			req[k] = v
+		}
+		writeLock.Lock()
+		defer writeLock.Unlock()
+		return conn.WriteJSON(req)
+	}
+	go func() {
+		ticker := time.NewTicker(WS_HEARTBEAT_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := send(sdkclient.WS_ACTION_HEARBEAT, nil); err != nil {
+					log.Warnf("[%s] send heartbeat error:%s", this.name, err)
+				}
+			case <-this.ctx.Done():
+				//unblock the reader
+				conn.Close()
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+	defer conn.Close()
+
+	err = send(sdkclient.WS_ACTION_SUBSCRIBE, map[string]interface{}{
+		sdkclient.WS_SUB_BLOCK_TX_HASH: true,
+	})
+	if err != nil {
+		return fmt.Errorf("send subscribe error:%s", err)
+	}
+	//catch up with blocks produced before the subscription
+	this.fetchHeight()
+	for {
+		conn.SetReadDeadline(time.Now().Add(WS_READ_TIMEOUT))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read error:%s", err)
+		}
+		resp := &sdkclient.WSResponse{}
+		if err := json.Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("unmarshal response error:%s", err)
+		}
+		if resp.Error != 0 {
+			return fmt.Errorf("%s error code:%d desc:%s", resp.Action, resp.Error, resp.Desc)
+		}
+		if resp.Action != sdkclient.WS_SUB_ACTION_BLOCK_TX_HASH {
+			continue
+		}
+		block := &struct{ Height uint32 }{}
+		if err := json.Unmarshal(resp.Result, block); err != nil {
+			return fmt.Errorf("unmarshal block error:%s", err)
+		}
+		this.publish(block.Height)
+	}
+}
+
+//SharedBlockSource fan out the heights of a BlockSource to several readers, so that the routes
+//relaying from the same chain share one subscription
+type SharedBlockSource struct {
+	source      BlockSource
+	subscribers []chan uint32
+}
+
+func NewSharedBlockSource(source BlockSource) *SharedBlockSource {
+	return &SharedBlockSource{source: source}
+}
+
+//Subscribe return a BlockSource receiving the heights of the shared source, it must be called
+//before Start
+func (this *SharedBlockSource) Subscribe() BlockSource {
+	heights := make(chan uint32, 1)
+	this.subscribers = append(this.subscribers, heights)
+	return heightsSource(heights)
+}
+
+//Start forwarding the heights to the subscribers until the shared source is closed
+func (this *SharedBlockSource) Start() {
+	go func() {
+		for height := range this.source.Heights() {
+			for _, heights := range this.subscribers {
+				publishLatest(heights, height)
+			}
+		}
+		for _, heights := range this.subscribers {
+			close(heights)
+		}
+	}()
+}
+
+//heightsSource is a BlockSource reading a channel fed by SharedBlockSource
+type heightsSource chan uint32
+
+func (this heightsSource) Heights() <-chan uint32 {
+	return this
+}
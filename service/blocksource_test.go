@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	sdkclient "github.com/ontio/ontology-go-sdk/client"
+)
+
+func waitHeight(t *testing.T, source BlockSource, height uint32) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case h := <-source.Heights():
+			if h >= height {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("height %d not received", height)
+		}
+	}
+}
+
+func fixedInterval(interval time.Duration) func() time.Duration {
+	return func() time.Duration { return interval }
+}
+
+func TestPollingBlockSource(t *testing.T) {
+	chain := newSimChain(testMainChainID)
+	ctx, cancel := context.WithCancel(context.Background())
+	source := NewBlockSource(ctx, "test", chain, "", fixedInterval(10*time.Millisecond))
+	waitHeight(t, source, 0)
+
+	height := chain.AddBlock(false)
+	waitHeight(t, source, height)
+
+	cancel()
+	for range source.Heights() {
+	}
+}
+
+func TestWebSocketBlockSource(t *testing.T) {
+	chain := newSimChain(testMainChainID)
+	blocks := make(chan uint32)
+	subscribed := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		req := make(map[string]interface{})
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req["Action"] != sdkclient.WS_ACTION_SUBSCRIBE || req[sdkclient.WS_SUB_BLOCK_TX_HASH] != true {
+			return
+		}
+		close(subscribed)
+		for height := range blocks {
+			result, _ := json.Marshal(map[string]interface{}{"Height": height})
+			conn.WriteJSON(&sdkclient.WSResponse{
+				Action: sdkclient.WS_SUB_ACTION_BLOCK_TX_HASH,
+				Result: result,
+			})
+		}
+	}))
+	defer server.Close()
+
+	//polling is so slow that only the subscription can deliver heights in time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := NewBlockSource(ctx, "test", chain, "ws"+strings.TrimPrefix(server.URL, "http"), fixedInterval(time.Hour))
+	<-subscribed
+	waitHeight(t, source, 0)
+	blocks <- 1
+	waitHeight(t, source, 1)
+	blocks <- 2
+	waitHeight(t, source, 2)
+	close(blocks)
+}
+
+func TestWebSocketBlockSourceFallback(t *testing.T) {
+	chain := newSimChain(testMainChainID)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := NewBlockSource(ctx, "test", chain, "ws://127.0.0.1:1", fixedInterval(10*time.Millisecond))
+
+	waitHeight(t, source, 0)
+	height := chain.AddBlock(false)
+	waitHeight(t, source, height)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	sdk "github.com/ontio/ontology-go-sdk"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+//ChainClient is the set of chain operations SyncService needs from a main or side chain node
+type ChainClient interface {
+	GetCurrentBlockHeight() (uint32, error)
+	GetBlockByHeight(height uint32) (*types.Block, error)
+	GetSmartContractEventByBlock(height uint32) ([]*sdkcom.SmartContactEvent, error)
+	GetStorage(contractAddress string, key []byte) ([]byte, error)
+	GetCrossStatesProof(height uint32, key []byte) (*sdkcom.CrossStatesProof, error)
+	//NewNativeInvokeTransaction build an unsigned native contract invocation
+	NewNativeInvokeTransaction(chainID, gasPrice, gasLimit uint64, version byte, contractAddress common.Address,
+		method string, params []interface{}) (*types.MutableTransaction, error)
+	SendTransaction(tx *types.MutableTransaction) (common.Uint256, error)
+	GetSmartContractEvent(txHash string) (*sdkcom.SmartContactEvent, error)
+	//GetOngBalance return the ONG balance of address in its smallest unit
+	GetOngBalance(address common.Address) (uint64, error)
+}
+
+//SdkClient adapts an ontology-go-sdk instance to ChainClient
+type SdkClient struct {
+	sdk *sdk.OntologySdk
+}
+
+var _ ChainClient = (*SdkClient)(nil)
+
+func NewSdkClient(ontSdk *sdk.OntologySdk) *SdkClient {
+	return &SdkClient{sdk: ontSdk}
+}
+
+func (this *SdkClient) GetCurrentBlockHeight() (uint32, error) {
+	return this.sdk.GetCurrentBlockHeight()
+}
+
+func (this *SdkClient) GetBlockByHeight(height uint32) (*types.Block, error) {
+	return this.sdk.GetBlockByHeight(height)
+}
+
+func (this *SdkClient) GetSmartContractEventByBlock(height uint32) ([]*sdkcom.SmartContactEvent, error) {
+	return this.sdk.GetSmartContractEventByBlock(height)
+}
+
+func (this *SdkClient) GetStorage(contractAddress string, key []byte) ([]byte, error) {
+	return this.sdk.GetStorage(contractAddress, key)
+}
+
+func (this *SdkClient) GetCrossStatesProof(height uint32, key []byte) (*sdkcom.CrossStatesProof, error) {
+	return this.sdk.GetCrossStatesProof(height, key)
+}
+
+func (this *SdkClient) NewNativeInvokeTransaction(chainID, gasPrice, gasLimit uint64, version byte, contractAddress common.Address,
+	method string, params []interface{}) (*types.MutableTransaction, error) {
+	return this.sdk.Native.NewNativeInvokeTransaction(chainID, gasPrice, gasLimit, version, contractAddress, method, params)
+}
+
+func (this *SdkClient) SendTransaction(tx *types.MutableTransaction) (common.Uint256, error) {
+	return this.sdk.SendTransaction(tx)
+}
+
+func (this *SdkClient) GetSmartContractEvent(txHash string) (*sdkcom.SmartContactEvent, error) {
+	return this.sdk.GetSmartContractEvent(txHash)
+}
+
+func (this *SdkClient) GetOngBalance(address common.Address) (uint64, error) {
+	return this.sdk.Native.Ong.BalanceOf(address)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ontio/crossChainClient/log"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	ocommon "github.com/ontio/ontology/common"
+)
+
+//ErrTxDropped is returned by TxTracker.Confirm when a transaction is not included before the timeout
+var ErrTxDropped = errors.New("tx not included before timeout")
+
+//TxResult is the execution result of an included transaction
+type TxResult struct {
+	TxHash  string
+	Success bool
+	//notify of the failed transaction, empty on success
+	Reason string
+}
+
+//TxTracker poll a chain for the event of submitted transactions until they are included
+type TxTracker struct {
+	name     string
+	client   ChainClient
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewTxTracker(name string, client ChainClient, interval, timeout time.Duration) *TxTracker {
+	return &TxTracker{
+		name:     name,
+		client:   client,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+//Confirm wait until txHash is included and return its result, ErrTxDropped if it is not included
+//before the timeout, or the ctx error if ctx is done first
+func (this *TxTracker) Confirm(ctx context.Context, txHash ocommon.Uint256) (*TxResult, error) {
+	hash := txHash.ToHexString()
+	ctx, cancel := context.WithTimeout(ctx, this.timeout)
+	defer cancel()
+	for {
+		event, err := this.client.GetSmartContractEvent(hash)
+		if err != nil {
+			countRpcError("GetSmartContractEvent")
+			log.Warnf("[%s] GetSmartContractEvent %s error:%s", this.name, hash, err)
+		} else if event != nil {
+			result := &TxResult{
+				TxHash:  hash,
+				Success: event.State == 1,
+			}
+			if !result.Success {
+				result.Reason = failureReason(event)
+				log.Errorf("[%s] tx %s failed:%s", this.name, hash, result.Reason)
+			} else {
+				log.Infof("[%s] tx %s confirmed", this.name, hash)
+			}
+			return result, nil
+		}
+		if !sleep(ctx, this.interval) {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, ErrTxDropped
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+//detach return a context which is not canceled with ctx, so that the block in flight can still be
+//relayed during a shutdown, but which expires grace after ctx is done
+func detach(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	detached, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-detached.Done():
+			return
+		}
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-detached.Done():
+		}
+	}()
+	return detached, cancel
+}
+
+//failureReason format the notifies of a failed transaction
+func failureReason(event *sdkcom.SmartContactEvent) string {
+	if len(event.Notify) == 0 {
+		return fmt.Sprintf("state %d without notify", event.State)
+	}
+	notify := event.Notify[len(event.Notify)-1]
+	return fmt.Sprintf("%v", notify.States)
+}
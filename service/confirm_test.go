@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetach(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	detached, cancelDetached := detach(ctx, 50*time.Millisecond)
+	defer cancelDetached()
+	cancel()
+	assert.Nil(t, detached.Err())
+	select {
+	case <-detached.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("detached context not done after the grace")
+	}
+}
+
+func TestConfirmDuringShutdown(t *testing.T) {
+	chain := newSimChain(testMainChainID)
+	tx, err := chain.NewNativeInvokeTransaction(testMainChainID, 0, 20000, 0, utils.HeaderSyncContractAddress,
+		header_sync.SYNC_BLOCK_HEADER, []interface{}{"param"})
+	assert.Nil(t, err)
+	assert.Nil(t, testSigner{1}.Sign(context.Background(), tx))
+	txHash, err := chain.SendTransaction(tx)
+	assert.Nil(t, err)
+	chain.FailNext("GetSmartContractEvent", 2)
+
+	//the shutdown started after the tx was sent
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	confirmCtx, cancelConfirm := detach(ctx, time.Second)
+	defer cancelConfirm()
+	tracker := NewTxTracker("test", chain, 10*time.Millisecond, time.Second)
+	result, err := tracker.Confirm(confirmCtx, txHash)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+}
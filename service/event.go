@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//ErrNotCrossChainTx is returned by DecodeCrossChainEvent for notifies which are not cross chain
+//transaction events of the cross_chain native contract, the caller should skip them
+var ErrNotCrossChainTx = errors.New("not a cross chain tx notify")
+
+//CrossChainEvent is a CREATE_CROSS_CHAIN_TX or PROCESS_CROSS_CHAIN_TX notify of the cross_chain
+//native contract, whose states are [name, chain id, request id]
+type CrossChainEvent struct {
+	Name string
+	//destination chain of a created request, source chain of a processed one
+	ChainID   uint64
+	RequestID uint64
+}
+
+//DecodeCrossChainEvent parse and validate a notify emitted by utils.CrossChainContractAddress
+func DecodeCrossChainEvent(notify *sdkcom.NotifyEventInfo) (*CrossChainEvent, error) {
+	if notify == nil {
+		return nil, fmt.Errorf("nil notify")
+	}
+	if notify.ContractAddress != utils.CrossChainContractAddress.ToHexString() {
+		return nil, ErrNotCrossChainTx
+	}
+	states, ok := notify.States.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("states is %T, expect array", notify.States)
+	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("empty states")
+	}
+	name, ok := states[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("states[0] is %T, expect string", states[0])
+	}
+	if name != cross_chain.CREATE_CROSS_CHAIN_TX && name != cross_chain.PROCESS_CROSS_CHAIN_TX {
+		return nil, ErrNotCrossChainTx
+	}
+	if len(states) < 3 {
+		return nil, fmt.Errorf("%s has %d states, expect 3", name, len(states))
+	}
+	chainID, err := decodeUint64(states[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s chain id error:%s", name, err)
+	}
+	requestID, err := decodeUint64(states[2])
+	if err != nil {
+		return nil, fmt.Errorf("%s request id error:%s", name, err)
+	}
+	return &CrossChainEvent{
+		Name:      name,
+		ChainID:   chainID,
+		RequestID: requestID,
+	}, nil
+}
+
+//decodeUint64 convert a json number state to uint64, rejecting values json can not carry exactly
+func decodeUint64(state interface{}) (uint64, error) {
+	switch v := state.(type) {
+	case float64:
+		if v < 0 || v > 1<<53 || v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not an unsigned integer", v)
+		}
+		return uint64(v), nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s is not an unsigned integer", v)
+		}
+		return uint64(n), nil
+	default:
+		return 0, fmt.Errorf("%T is not a number", state)
+	}
+}
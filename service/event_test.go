@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCrossChainEvent(t *testing.T) {
+	crossChain := utils.CrossChainContractAddress.ToHexString()
+	tests := []struct {
+		name   string
+		notify *sdkcom.NotifyEventInfo
+		event  *CrossChainEvent
+		err    error
+	}{
+		{
+			name:   "create",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), float64(42)}},
+			event:  &CrossChainEvent{Name: cross_chain.CREATE_CROSS_CHAIN_TX, ChainID: 1, RequestID: 42},
+		},
+		{
+			name:   "process",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.PROCESS_CROSS_CHAIN_TX, float64(0), float64(7)}},
+			event:  &CrossChainEvent{Name: cross_chain.PROCESS_CROSS_CHAIN_TX, ChainID: 0, RequestID: 7},
+		},
+		{
+			name:   "json number",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, json.Number("3"), json.Number("9007199254740993")}},
+			event:  &CrossChainEvent{Name: cross_chain.CREATE_CROSS_CHAIN_TX, ChainID: 3, RequestID: 9007199254740993},
+		},
+		{
+			name:   "extra states",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), float64(2), "extra"}},
+			event:  &CrossChainEvent{Name: cross_chain.CREATE_CROSS_CHAIN_TX, ChainID: 1, RequestID: 2},
+		},
+		{
+			name:   "other contract",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: utils.OngContractAddress.ToHexString(), States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), float64(2)}},
+			err:    ErrNotCrossChainTx,
+		},
+		{
+			name:   "other event",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{"transfer", "a", "b", float64(1)}},
+			err:    ErrNotCrossChainTx,
+		},
+		{name: "nil notify"},
+		{
+			name:   "states not array",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: "0a0b"},
+		},
+		{
+			name:   "empty states",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{}},
+		},
+		{
+			name:   "name not string",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{float64(1), float64(1), float64(2)}},
+		},
+		{
+			name:   "missing request id",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1)}},
+		},
+		{
+			name:   "request id string",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), "2a"}},
+		},
+		{
+			name:   "negative chain id",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(-1), float64(2)}},
+		},
+		{
+			name:   "fractional request id",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), 2.5}},
+		},
+		{
+			name:   "request id beyond float precision",
+			notify: &sdkcom.NotifyEventInfo{ContractAddress: crossChain, States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(1), float64(1 << 60)}},
+		},
+	}
+	for _, test := range tests {
+		event, err := DecodeCrossChainEvent(test.notify)
+		if test.event != nil {
+			assert.Nil(t, err, test.name)
+			assert.Equal(t, test.event, event, test.name)
+			continue
+		}
+		assert.Nil(t, event, test.name)
+		if test.err != nil {
+			assert.Equal(t, test.err, err, test.name)
+		} else {
+			assert.NotNil(t, err, test.name)
+			assert.NotEqual(t, ErrNotCrossChainTx, err, test.name)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+//how long the readiness check waits for a chain node
+const READY_RPC_TIMEOUT = 5 * time.Second
+
+//HealthCheck is the result of one check of /healthz or /readyz
+type HealthCheck struct {
+	Name   string
+	Ok     bool
+	Detail string
+}
+
+//HealthReport is the result of the checks of /healthz or /readyz, Ok if all of them passed
+type HealthReport struct {
+	Ok     bool
+	Checks []*HealthCheck
+}
+
+func newHealthReport(checks ...*HealthCheck) *HealthReport {
+	report := &HealthReport{Ok: true, Checks: checks}
+	for _, check := range checks {
+		report.Ok = report.Ok && check.Ok
+	}
+	return report
+}
+
+//Liveness check that the relay loop of every route is running
+func (this *SyncService) Liveness() *HealthReport {
+	checks := make([]*HealthCheck, 0, len(this.routes))
+	for _, route := range this.routes {
+		route.state.lock.RLock()
+		check := &HealthCheck{Name: "route " + route.Name, Ok: route.state.running, Detail: "running"}
+		route.state.lock.RUnlock()
+		if !check.Ok {
+			check.Detail = "relay loop stopped"
+		}
+		checks = append(checks, check)
+	}
+	return newHealthReport(checks...)
+}
+
+//Readiness check that every chain node is reachable, and that every route lags behind its source
+//chain by at most ReadyMaxLag blocks and relayed a block or was caught up in the last ReadyTimeout
+//minutes
+func (this *SyncService) Readiness() *HealthReport {
+	cfg := this.GetConfig()
+	checks := this.checkChains()
+	for _, route := range this.routes {
+		checks = append(checks, route.checkProgress(cfg.ReadyMaxLag, time.Duration(cfg.ReadyTimeout)*time.Minute))
+	}
+	return newHealthReport(checks...)
+}
+
+//checkChains query the height of every chain concurrently, waiting at most READY_RPC_TIMEOUT
+func (this *SyncService) checkChains() []*HealthCheck {
+	chainIDs := make([]uint64, 0, len(this.chains))
+	for chainID := range this.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+	results := make([]chan *HealthCheck, len(chainIDs))
+	for i, chainID := range chainIDs {
+		results[i] = make(chan *HealthCheck, 1)
+		go func(chain *Chain, result chan *HealthCheck) {
+			check := &HealthCheck{Name: fmt.Sprintf("chain %d", chain.ChainID)}
+			height, err := chain.Client.GetCurrentBlockHeight()
+			if err != nil {
+				countRpcError("GetCurrentBlockHeight")
+				check.Detail = fmt.Sprintf("GetCurrentBlockHeight error:%s", err)
+			} else {
+				check.Ok = true
+				check.Detail = fmt.Sprintf("height %d", height)
+			}
+			result <- check
+		}(this.chains[chainID], results[i])
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), READY_RPC_TIMEOUT)
+	defer cancel()
+	checks := make([]*HealthCheck, 0, len(chainIDs))
+	for i, chainID := range chainIDs {
+		select {
+		case check := <-results[i]:
+			checks = append(checks, check)
+		case <-ctx.Done():
+			checks = append(checks, &HealthCheck{
+				Name:   fmt.Sprintf("chain %d", chainID),
+				Detail: fmt.Sprintf("no answer in %s", READY_RPC_TIMEOUT),
+			})
+		}
+	}
+	return checks
+}
+
+//checkProgress check the lag and the last success of the route, a zero maxLag or timeout is no limit
+func (this *Route) checkProgress(maxLag uint64, timeout time.Duration) *HealthCheck {
+	this.state.lock.RLock()
+	defer this.state.lock.RUnlock()
+	lag := uint64(this.lag())
+	idle := time.Since(this.state.lastSuccess).Truncate(time.Second)
+	check := &HealthCheck{
+		Name:   "route " + this.Name,
+		Ok:     this.state.running,
+		Detail: fmt.Sprintf("lag %d blocks, last success %s ago", lag, idle),
+	}
+	if !this.state.running {
+		check.Detail = "relay loop stopped"
+		return check
+	}
+	if maxLag != 0 && lag > maxLag {
+		check.Ok = false
+		check.Detail += fmt.Sprintf(", more than %d blocks behind", maxLag)
+	}
+	if timeout != 0 && idle > timeout {
+		check.Ok = false
+		check.Detail += fmt.Sprintf(", no success for more than %s", timeout)
+	}
+	if this.state.lastError != "" {
+		check.Detail += ", last error: " + this.state.lastError
+	}
+	return check
+}
+
+//HealthHandler serve /healthz and /readyz. The relayer is alive but not ready until the service is
+//set, which happens once the wallet is unlocked
+type HealthHandler struct {
+	service atomic.Value
+}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+//SetService set the running service to check
+func (this *HealthHandler) SetService(syncService *SyncService) {
+	this.service.Store(syncService)
+}
+
+func (this *HealthHandler) getService() *SyncService {
+	syncService, _ := this.service.Load().(*SyncService)
+	return syncService
+}
+
+//Register add the /healthz and /readyz endpoints to mux
+func (this *HealthHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, this.Liveness())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, this.Readiness())
+	})
+}
+
+func (this *HealthHandler) Liveness() *HealthReport {
+	syncService := this.getService()
+	if syncService == nil {
+		return newHealthReport(&HealthCheck{Name: "service", Ok: true, Detail: "starting"})
+	}
+	return syncService.Liveness()
+}
+
+func (this *HealthHandler) Readiness() *HealthReport {
+	syncService := this.getService()
+	if syncService == nil {
+		return newHealthReport(&HealthCheck{Name: "accounts", Detail: "wallet not unlocked"})
+	}
+	checks := []*HealthCheck{{Name: "accounts", Ok: true, Detail: "unlocked"}}
+	return newHealthReport(append(checks, syncService.Readiness().Checks...)...)
+}
+
+//writeHealthReport answer report as json, with status 503 if a check failed
+func writeHealthReport(w http.ResponseWriter, report *HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
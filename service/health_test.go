@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness(t *testing.T) {
+	syncService, mainChain, _, cleanup := newTestService(t)
+	defer cleanup()
+	syncService.GetConfig().ReadyMaxLag = 10
+	syncService.GetConfig().ReadyTimeout = 1
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	for _, route := range syncService.routes {
+		route.setRunning(true)
+	}
+
+	route.setSyncHeight(5)
+	route.observe(20)
+	mainChain.FailNext("GetCurrentBlockHeight", 1)
+	report := syncService.Readiness()
+	assert.False(t, report.Ok)
+	assert.Equal(t, 4, len(report.Checks))
+	assert.Equal(t, "chain 0", report.Checks[0].Name)
+	assert.False(t, report.Checks[0].Ok)
+	assert.True(t, report.Checks[1].Ok)
+	assert.Equal(t, "height 0", report.Checks[1].Detail)
+	assert.Equal(t, "route 0->1", report.Checks[2].Name)
+	assert.False(t, report.Checks[2].Ok)
+	assert.Equal(t, "lag 15 blocks, last success 0s ago, more than 10 blocks behind", report.Checks[2].Detail)
+	assert.True(t, report.Checks[3].Ok)
+
+	//relaying a block is a success even if the route is still behind
+	route.state.lastSuccess = time.Now().Add(-2 * time.Minute)
+	route.observe(10)
+	route.advance(5)
+	assert.True(t, syncService.Readiness().Ok)
+
+	route.state.lastSuccess = time.Now().Add(-2 * time.Minute)
+	route.fail(context.DeadlineExceeded)
+	report = syncService.Readiness()
+	assert.False(t, report.Ok)
+	assert.Equal(t, "lag 4 blocks, last success 2m0s ago, no success for more than 1m0s, last error: "+
+		context.DeadlineExceeded.Error(), report.Checks[2].Detail)
+
+	//a route caught up is a success without relaying
+	route.setSyncHeight(10)
+	route.observe(10)
+	assert.True(t, syncService.Readiness().Ok)
+}
+
+func getHealth(t *testing.T, server *httptest.Server, path string) (int, *HealthReport) {
+	resp, err := http.Get(server.URL + path)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	report := &HealthReport{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(report))
+	return resp.StatusCode, report
+}
+
+func TestHealthHandler(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	health := NewHealthHandler()
+	mux := http.NewServeMux()
+	health.Register(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	status, _ := getHealth(t, server, "/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	status, report := getHealth(t, server, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, []*HealthCheck{{Name: "accounts", Detail: "wallet not unlocked"}}, report.Checks)
+
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	sideChain.AddBlock(false)
+	syncService.Run(context.Background())
+	health.SetService(syncService)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, report = getHealth(t, server, "/readyz")
+		if status == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("not ready: %v", report.Checks)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 5, len(report.Checks))
+	status, _ = getHealth(t, server, "/healthz")
+	assert.Equal(t, http.StatusOK, status)
+
+	syncService.Stop()
+	status, report = getHealth(t, server, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "relay loop stopped", report.Checks[0].Detail)
+}
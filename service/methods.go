@@ -1,11 +1,18 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/ontio/crossChainClient/common"
+	"github.com/ontio/crossChainClient/config"
 	"github.com/ontio/crossChainClient/log"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
 	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -13,48 +20,104 @@ import (
 
 var codeVersion = byte(0)
 
+//max headers SyncHeaders submit in one SYNC_BLOCK_HEADER tx
+const MAX_HEADERS_PER_TX = 20
+
+//upper bound of the backoff of a route failing to relay a block, RetryMaxDelay if lower
+const MAX_FAILURE_DELAY = 10 * time.Minute
+
+//GetConfig return the current config of the service
+func (this *SyncService) GetConfig() *config.Config {
+	return this.config.Load().(*config.Config)
+}
+
 func (this *SyncService) GetMainChainID() uint64 {
-	return this.config.MainChainID
+	return this.GetConfig().MainChainID
 }
 
-func (this *SyncService) GetSideChainID() uint64 {
-	return this.config.SideChainID
+func (this *SyncService) GetPollingInterval() time.Duration {
+	cfg := this.GetConfig()
+	if cfg.PollingInterval == 0 {
+		return config.DEFAULT_POLLING_INTERVAL * time.Millisecond
+	}
+	return time.Duration(cfg.PollingInterval) * time.Millisecond
 }
 
-func (this *SyncService) GetGasPrice() uint64 {
-	return this.config.GasPrice
+func (this *SyncService) GetConfirmTimeout() time.Duration {
+	cfg := this.GetConfig()
+	if cfg.ConfirmTimeout == 0 {
+		return config.DEFAULT_CONFIRM_TIMEOUT * time.Millisecond
+	}
+	return time.Duration(cfg.ConfirmTimeout) * time.Millisecond
 }
 
-func (this *SyncService) GetGasLimit() uint64 {
-	return this.config.GasLimit
+func (this *SyncService) GetShutdownTimeout() time.Duration {
+	cfg := this.GetConfig()
+	if cfg.ShutdownTimeout == 0 {
+		return config.DEFAULT_SHUTDOWN_TIMEOUT * time.Second
+	}
+	return time.Duration(cfg.ShutdownTimeout) * time.Second
 }
 
-func (this *SyncService) GetCurrentSideChainSyncHeight(maiChainID uint64) (uint32, error) {
-	contractAddress := utils.HeaderSyncContractAddress
-	maiChainIDBytes, err := utils.GetUint64Bytes(maiChainID)
-	if err != nil {
-		return 0, fmt.Errorf("GetUint32Bytes, get viewBytes error: %s", err)
+//GetShutdownGrace return how long a shutdown waits for the blocks in flight, shorter than the
+//ShutdownTimeout to leave the relay loops the time to checkpoint and exit
+func (this *SyncService) GetShutdownGrace() time.Duration {
+	return this.GetShutdownTimeout() / 2
+}
+
+func (this *SyncService) GetRetryPolicy() *RetryPolicy {
+	cfg := this.GetConfig()
+	return &RetryPolicy{
+		MaxAttempts: cfg.RetryMaxAttempts,
+		BaseDelay:   time.Duration(cfg.RetryBaseDelay) * time.Millisecond,
+		MaxDelay:    time.Duration(cfg.RetryMaxDelay) * time.Millisecond,
+		Jitter:      cfg.RetryJitter,
 	}
-	key := common.ConcatKey([]byte(header_sync.CURRENT_HEIGHT), maiChainIDBytes)
-	value, err := this.sideSdk.ClientMgr.GetStorage(contractAddress.ToHexString(), key)
-	if err != nil {
-		return 0, fmt.Errorf("getStorage error: %s", err)
+}
+
+//failureDelay return the backoff of a route after failures consecutive failed attempts, at most
+//MAX_FAILURE_DELAY before jitter even if RetryMaxDelay is 0
+func (this *SyncService) failureDelay(failures uint64) time.Duration {
+	policy := this.GetRetryPolicy()
+	if policy.MaxDelay == 0 || policy.MaxDelay > MAX_FAILURE_DELAY {
+		policy.MaxDelay = MAX_FAILURE_DELAY
 	}
-	height, err := utils.GetBytesUint32(value)
-	if err != nil {
-		return 0, fmt.Errorf("GetBytesUint32, get height error: %s", err)
+	return policy.Delay(failures)
+}
+
+//getGas return the current gas price and limit of chain
+func (this *SyncService) getGas(chain *Chain) (uint64, uint64) {
+	chainConfig := this.GetConfig().GetChain(chain.ChainID)
+	if chainConfig == nil {
+		return chain.GasPrice, chain.GasLimit
 	}
-	return height, nil
+	return chainConfig.GasPrice, chainConfig.GasLimit
 }
 
-func (this *SyncService) GetCurrentMainChainSyncHeight(sideChainID uint64) (uint32, error) {
+//retry run fn calling the chain node method name under the configured retry policy, counting its errors
+func (this *SyncService) retry(ctx context.Context, name string, fn func() error) error {
+	return this.GetRetryPolicy().Do(ctx, name, func() error {
+		err := fn()
+		if err != nil {
+			countRpcError(name)
+		}
+		return err
+	})
+}
+
+//GetSyncHeight return the current height of chain fromChainID in the header_sync contract of chain
+func (this *SyncService) GetSyncHeight(ctx context.Context, chain *Chain, fromChainID uint64) (uint32, error) {
 	contractAddress := utils.HeaderSyncContractAddress
-	sideChainIDBytes, err := utils.GetUint64Bytes(sideChainID)
+	fromChainIDBytes, err := utils.GetUint64Bytes(fromChainID)
 	if err != nil {
-		return 0, fmt.Errorf("GetUint32Bytes, get viewBytes error: %s", err)
+		return 0, fmt.Errorf("GetUint64Bytes, get fromChainIDBytes error: %s", err)
 	}
-	key := common.ConcatKey([]byte(header_sync.CURRENT_HEIGHT), sideChainIDBytes)
-	value, err := this.mainSdk.ClientMgr.GetStorage(contractAddress.ToHexString(), key)
+	key := common.ConcatKey([]byte(header_sync.CURRENT_HEIGHT), fromChainIDBytes)
+	var value []byte
+	err = this.retry(ctx, "GetStorage", func() (err error) {
+		value, err = chain.Client.GetStorage(contractAddress.ToHexString(), key)
+		return
+	})
 	if err != nil {
 		return 0, fmt.Errorf("getStorage error: %s", err)
 	}
@@ -65,152 +128,303 @@ func (this *SyncService) GetCurrentMainChainSyncHeight(sideChainID uint64) (uint
 	return height, nil
 }
 
-func (this *SyncService) syncHeaderToMain(height uint32) error {
-	chainIDBytes, err := utils.GetUint64Bytes(this.GetSideChainID())
-	if err != nil {
-		return fmt.Errorf("[syncHeaderToMain] chainIDBytes, getUint32Bytes error: %v", err)
-	}
-	heightBytes, err := utils.GetUint32Bytes(height)
+//IsRequestProcessed check in the cross_chain contract storage of client whether the request requestID
+//from chain fromChainID has already been processed, reading the DONE_TX prefix followed by both ids as
+//little endian uint64. sendProof reads it back after each request relayed to detect another layout
+func (this *SyncService) IsRequestProcessed(ctx context.Context, client ChainClient, fromChainID, requestID uint64) (bool, error) {
+	fromChainIDBytes, err := utils.GetUint64Bytes(fromChainID)
 	if err != nil {
-		return fmt.Errorf("[syncHeaderToMain] heightBytes, getUint32Bytes error: %v", err)
-	}
-	v, err := this.mainSdk.GetStorage(utils.HeaderSyncContractAddress.ToHexString(),
-		common.ConcatKey([]byte(header_sync.HEADER_INDEX), chainIDBytes, heightBytes))
-	if len(v) != 0 {
-		return nil
+		return false, fmt.Errorf("GetUint64Bytes, get fromChainIDBytes error: %s", err)
 	}
-	contractAddress := utils.HeaderSyncContractAddress
-	method := header_sync.SYNC_BLOCK_HEADER
-	block, err := this.sideSdk.GetBlockByHeight(height)
+	requestIDBytes, err := utils.GetUint64Bytes(requestID)
 	if err != nil {
-		log.Errorf("[syncHeaderToMain] this.mainSdk.GetBlockByHeight error:%s", err)
+		return false, fmt.Errorf("GetUint64Bytes, get requestIDBytes error: %s", err)
 	}
-	param := &header_sync.SyncBlockHeaderParam{
-		Headers: [][]byte{block.Header.ToArray()},
-	}
-	txHash, err := this.mainSdk.Native.InvokeNativeContract(this.GetMainChainID(), this.GetGasPrice(), this.GetGasLimit(), this.account, codeVersion,
-		contractAddress, method, []interface{}{param})
+	var v []byte
+	err = this.retry(ctx, "GetStorage", func() (err error) {
+		v, err = client.GetStorage(utils.CrossChainContractAddress.ToHexString(),
+			common.ConcatKey([]byte(cross_chain.DONE_TX), fromChainIDBytes, requestIDBytes))
+		return
+	})
 	if err != nil {
-		return fmt.Errorf("[syncHeaderToMain] invokeNativeContract error: %s", err)
+		return false, fmt.Errorf("getStorage error: %s", err)
 	}
-	log.Infof("[syncHeaderToMain] syncHeaderToMain txHash is :", txHash.ToHexString())
-	this.waitForMainBlock()
-	this.waitForSideBlock()
-	return nil
+	return len(v) != 0, nil
 }
 
-func (this *SyncService) sendProofToMain(requestID uint64, height uint32) error {
-	//TODO: filter if tx is done
-
-	chainIDBytes, err := utils.GetUint64Bytes(this.GetMainChainID())
+//syncHeader sync the header at height of route.From to route.To unless it is already synced
+func (this *SyncService) syncHeader(ctx context.Context, route *Route, height uint32) error {
+	synced, err := this.isHeaderSynced(ctx, route, height)
 	if err != nil {
-		return fmt.Errorf("[sendProofToMain] GetUint32Bytes error:%s", err)
+		return fmt.Errorf("[syncHeader] %s", err)
 	}
-	prefix, err := utils.GetUint64Bytes(requestID)
+	if synced {
+		return nil
+	}
+	var block *types.Block
+	err = this.retry(ctx, "GetBlockByHeight", func() (err error) {
+		block, err = route.From.Client.GetBlockByHeight(height)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf("[sendProofToMain] GetUint64Bytes error:%s", err)
+		return fmt.Errorf("[syncHeader] GetBlockByHeight error:%s", err)
 	}
-	key := utils.ConcatKey(utils.CrossChainContractAddress, []byte(cross_chain.REQUEST), chainIDBytes, prefix)
-	crossStatesProof, err := this.sideSdk.GetCrossStatesProof(height, key)
+	err = this.submitHeaders(ctx, route, []uint32{height}, [][]byte{block.Header.ToArray()})
 	if err != nil {
-		return fmt.Errorf("[sendProofToMain] this.sideSdk.GetCrossStatesProof error: %s", err)
+		return fmt.Errorf("[syncHeader] %s", err)
 	}
+	return nil
+}
 
-	contractAddress := utils.CrossChainContractAddress
-	method := cross_chain.PROCESS_CROSS_CHAIN_TX
-	param := &cross_chain.ProcessCrossChainTxParam{
-		Address:     this.account.Address,
-		FromChainID: this.GetSideChainID(),
-		Height:      height + 1,
-		Proof:       crossStatesProof.AuditPath,
+//HeaderSyncResult is the outcome of SyncHeaders
+type HeaderSyncResult struct {
+	//heights whose header has been synced
+	Synced []uint32
+	//heights whose header was already in the header_sync contract of the destination chain
+	Present []uint32
+}
+
+//SyncHeaders sync the headers from start to end of route.From to route.To, or only the key headers
+//with keyOnly, skipping the ones already synced and submitting up to MAX_HEADERS_PER_TX headers per
+//tx. It return the heights synced and already present so far, even on error
+func (this *SyncService) SyncHeaders(ctx context.Context, route *Route, start, end uint32,
+	keyOnly bool) (*HeaderSyncResult, error) {
+	result := &HeaderSyncResult{Synced: make([]uint32, 0), Present: make([]uint32, 0)}
+	headers := make([][]byte, 0, MAX_HEADERS_PER_TX)
+	heights := make([]uint32, 0, MAX_HEADERS_PER_TX)
+	flush := func() error {
+		if len(headers) == 0 {
+			return nil
+		}
+		log.Infof("[%s] sync headers %v", route.Name, heights)
+		if err := this.submitHeaders(ctx, route, heights, headers); err != nil {
+			return fmt.Errorf("sync headers %v error:%s", heights, err)
+		}
+		result.Synced = append(result.Synced, heights...)
+		headers, heights = headers[:0], heights[:0]
+		return nil
 	}
-	txHash, err := this.mainSdk.Native.InvokeNativeContract(this.GetSideChainID(), this.GetGasPrice(), this.GetGasLimit(), this.account, codeVersion,
-		contractAddress, method, []interface{}{param})
-	if err != nil {
-		return fmt.Errorf("[sendProofToMain] invokeNativeContract error: %s", err)
+	//uint64 so that end may be math.MaxUint32
+	for h := uint64(start); h <= uint64(end); h++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		height := uint32(h)
+		var block *types.Block
+		if keyOnly {
+			err := this.retry(ctx, "GetBlockByHeight", func() (err error) {
+				block, err = route.From.Client.GetBlockByHeight(height)
+				return
+			})
+			if err != nil {
+				return result, fmt.Errorf("GetBlockByHeight error:%s", err)
+			}
+			key, err := isKeyHeader(block.Header)
+			if err != nil {
+				return result, fmt.Errorf("block %d: %s", height, err)
+			}
+			if !key {
+				continue
+			}
+		}
+		synced, err := this.isHeaderSynced(ctx, route, height)
+		if err != nil {
+			return result, err
+		}
+		if synced {
+			result.Present = append(result.Present, height)
+			continue
+		}
+		if block == nil {
+			err = this.retry(ctx, "GetBlockByHeight", func() (err error) {
+				block, err = route.From.Client.GetBlockByHeight(height)
+				return
+			})
+			if err != nil {
+				return result, fmt.Errorf("GetBlockByHeight error:%s", err)
+			}
+		}
+		headers = append(headers, block.Header.ToArray())
+		heights = append(heights, height)
+		if len(headers) == MAX_HEADERS_PER_TX {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
 	}
-	log.Infof("[sendProofToMain] sendProofToMain txHash is :", txHash.ToHexString())
-	return nil
+	return result, flush()
 }
 
-func (this *SyncService) syncHeaderToSide(height uint32) error {
-	chainIDBytes, err := utils.GetUint64Bytes(this.GetMainChainID())
+//isHeaderSynced check the HEADER_INDEX storage of the header_sync contract of route.To for the header
+//at height of route.From
+func (this *SyncService) isHeaderSynced(ctx context.Context, route *Route, height uint32) (bool, error) {
+	chainIDBytes, err := utils.GetUint64Bytes(route.From.ChainID)
 	if err != nil {
-		return fmt.Errorf("[syncHeaderToSide] chainIDBytes, getUint32Bytes error: %v", err)
+		return false, fmt.Errorf("chainIDBytes, getUint64Bytes error: %v", err)
 	}
 	heightBytes, err := utils.GetUint32Bytes(height)
 	if err != nil {
-		return fmt.Errorf("[syncHeaderToSide] heightBytes, getUint32Bytes error: %v", err)
+		return false, fmt.Errorf("heightBytes, getUint32Bytes error: %v", err)
 	}
-	v, err := this.sideSdk.GetStorage(utils.HeaderSyncContractAddress.ToHexString(),
-		common.ConcatKey([]byte(header_sync.HEADER_INDEX), chainIDBytes, heightBytes))
-	if len(v) != 0 {
-		return nil
-	}
-	contractAddress := utils.HeaderSyncContractAddress
-	method := header_sync.SYNC_BLOCK_HEADER
-	block, err := this.mainSdk.GetBlockByHeight(height)
+	var v []byte
+	err = this.retry(ctx, "GetStorage", func() (err error) {
+		v, err = route.To.Client.GetStorage(utils.HeaderSyncContractAddress.ToHexString(),
+			common.ConcatKey([]byte(header_sync.HEADER_INDEX), chainIDBytes, heightBytes))
+		return
+	})
 	if err != nil {
-		log.Errorf("[syncHeaderToSide] this.mainSdk.GetBlockByHeight error:%s", err)
+		return false, fmt.Errorf("GetStorage error:%s", err)
 	}
+	return len(v) != 0, nil
+}
+
+//submitHeaders submit the raw headers at heights of route.From to the header_sync contract of route.To
+//in one tx
+func (this *SyncService) submitHeaders(ctx context.Context, route *Route, heights []uint32, headers [][]byte) error {
 	param := &header_sync.SyncBlockHeaderParam{
-		Headers: [][]byte{block.Header.ToArray()},
+		Headers: headers,
 	}
-	txHash, err := this.sideSdk.Native.InvokeNativeContract(this.GetSideChainID(), this.GetGasPrice(), this.GetGasLimit(), this.account, codeVersion,
-		contractAddress, method, []interface{}{param})
+	//the headers of a tx are synced together, the last one tells whether it was executed
+	last := heights[len(heights)-1]
+	err := this.submit(ctx, route, route.To.Accounts.Header, utils.HeaderSyncContractAddress,
+		header_sync.SYNC_BLOCK_HEADER, param, func() (bool, error) {
+			return this.isHeaderSynced(ctx, route, last)
+		})
 	if err != nil {
-		return fmt.Errorf("[syncHeaderToSide] invokeNativeContract error: %s", err)
+		return fmt.Errorf("this.submit error: %s", err)
 	}
-	log.Infof("[syncHeaderToSide] syncHeaderToSide txHash is :", txHash.ToHexString())
-	this.waitForSideBlock()
-	this.waitForSideBlock()
+	headersSyncedCounter.WithLabelValues(route.metricLabels()...).Add(float64(len(headers)))
 	return nil
 }
 
-func (this *SyncService) sendProofToSide(requestID uint64, height uint32) error {
-	//TODO: filter if tx is done
+//isKeyHeader return whether header changes the consensus config, which every chain syncing the
+//headers of its chain needs
+func isKeyHeader(header *types.Header) (bool, error) {
+	blkInfo := &vconfig.VbftBlockInfo{}
+	if err := json.Unmarshal(header.ConsensusPayload, blkInfo); err != nil {
+		return false, fmt.Errorf("unmarshal blockInfo error:%s", err)
+	}
+	return blkInfo.NewChainConfig != nil, nil
+}
 
-	chainIDBytes, err := utils.GetUint64Bytes(this.GetSideChainID())
+//sendProof submit to route.To the proof of request requestID created at height of route.From
+func (this *SyncService) sendProof(ctx context.Context, route *Route, requestID uint64, height uint32) error {
+	done, err := this.IsRequestProcessed(ctx, route.To.Client, route.From.ChainID, requestID)
+	if err != nil {
+		return fmt.Errorf("[sendProof] this.IsRequestProcessed error:%s", err)
+	}
+	if done {
+		log.Infof("[%s] request %d already processed, skip", route.Name, requestID)
+		return nil
+	}
+	chainIDBytes, err := utils.GetUint64Bytes(route.To.ChainID)
 	if err != nil {
-		return fmt.Errorf("[sendProofToSide] GetUint32Bytes error:%s", err)
+		return fmt.Errorf("[sendProof] GetUint64Bytes error:%s", err)
 	}
 	prefix, err := utils.GetUint64Bytes(requestID)
 	if err != nil {
-		return fmt.Errorf("[sendProofToSide] GetUint64Bytes error:%s", err)
+		return fmt.Errorf("[sendProof] GetUint64Bytes error:%s", err)
 	}
 	key := utils.ConcatKey(utils.CrossChainContractAddress, []byte(cross_chain.REQUEST), chainIDBytes, prefix)
-	crossStatesProof, err := this.mainSdk.GetCrossStatesProof(height, key)
+	var crossStatesProof *sdkcom.CrossStatesProof
+	start := time.Now()
+	err = this.retry(ctx, "GetCrossStatesProof", func() (err error) {
+		crossStatesProof, err = route.From.Client.GetCrossStatesProof(height, key)
+		return
+	})
+	proofFetchHistogram.WithLabelValues(route.metricLabels()...).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return fmt.Errorf("[sendProofToSide] this.mainSdk.GetCrossStatesProof error: %s", err)
+		return fmt.Errorf("[sendProof] GetCrossStatesProof error: %s", err)
 	}
 
 	contractAddress := utils.CrossChainContractAddress
 	method := cross_chain.PROCESS_CROSS_CHAIN_TX
 	param := &cross_chain.ProcessCrossChainTxParam{
-		Address:     this.account.Address,
-		FromChainID: this.GetMainChainID(),
+		Address:     route.To.Accounts.Proof.Address(),
+		FromChainID: route.From.ChainID,
 		Height:      height + 1,
 		Proof:       crossStatesProof.AuditPath,
 	}
-	txHash, err := this.sideSdk.Native.InvokeNativeContract(this.GetSideChainID(), this.GetGasPrice(), this.GetGasLimit(), this.account, codeVersion,
-		contractAddress, method, []interface{}{param})
+	err = this.submit(ctx, route, route.To.Accounts.Proof, contractAddress, method, param, func() (bool, error) {
+		return this.IsRequestProcessed(ctx, route.To.Client, route.From.ChainID, requestID)
+	})
 	if err != nil {
-		return fmt.Errorf("[sendProofToSide] invokeNativeContract error: %s", err)
+		return fmt.Errorf("[sendProof] this.submit error: %s", err)
 	}
-	log.Infof("[sendProofToSide] sendProofToSide txHash is :", txHash.ToHexString())
-	return nil
-}
-
-func (this *SyncService) waitForMainBlock() {
-	_, err := this.mainSdk.WaitForGenerateBlock(30*time.Second, 1)
+	//a request relayed but not read as processed means the processed check never matches
+	done, err = this.IsRequestProcessed(ctx, route.To.Client, route.From.ChainID, requestID)
 	if err != nil {
-		log.Errorf("waitForMainBlock error:%s", err)
+		log.Warnf("[%s] request %d this.IsRequestProcessed error:%s", route.Name, requestID, err)
+	} else if !done {
+		processedMismatchCounter.WithLabelValues(route.metricLabels()...).Inc()
+		log.Errorf("[%s] request %d relayed but not read as processed from the %s key of the cross_chain contract",
+			route.Name, requestID, cross_chain.DONE_TX)
 	}
+	return nil
 }
 
-func (this *SyncService) waitForSideBlock() {
-	_, err := this.sideSdk.WaitForGenerateBlock(30*time.Second, 1)
+//submit invoke method of a native contract on route.To signed by signer and wait for the tx to be
+//confirmed, building and submitting it again under the retry policy if it fails or is dropped. Before
+//each new attempt done tells whether a previous tx was executed after all
+func (this *SyncService) submit(ctx context.Context, route *Route, signer Signer,
+	contractAddress ocommon.Address, method string, param interface{}, done func() (bool, error)) error {
+	chain := route.To
+	labels := route.metricLabels(method)
+	tracker := NewTxTracker(route.Name, chain.Client, this.GetPollingInterval(), this.GetConfirmTimeout())
+	start := time.Now()
+	attempt := 0
+	//not this.retry, the errors are counted by method below
+	err := this.GetRetryPolicy().Do(ctx, route.Name, func() error {
+		attempt++
+		//a tx not confirmed in time, or reported dropped, may still be included later
+		if attempt > 1 {
+			ok, err := done()
+			if err != nil {
+				return fmt.Errorf("check previous %s error: %s", method, err)
+			}
+			if ok {
+				log.Infof("[%s] previous %s tx executed, skip", route.Name, method)
+				return nil
+			}
+		}
+		gasPrice, gasLimit := this.getGas(chain)
+		tx, err := chain.Client.NewNativeInvokeTransaction(chain.ChainID, gasPrice, gasLimit, codeVersion,
+			contractAddress, method, []interface{}{param})
+		if err != nil {
+			return fmt.Errorf("newNativeInvokeTransaction error: %s", err)
+		}
+		err = signer.Sign(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("sign tx error: %s", err)
+		}
+		txHash, err := chain.Client.SendTransaction(tx)
+		if err != nil {
+			countRpcError("SendTransaction")
+			return fmt.Errorf("sendTransaction error: %s", err)
+		}
+		txsSubmittedCounter.WithLabelValues(labels...).Inc()
+		record := route.recordTx(method, txHash.ToHexString())
+		log.Infof("[%s] %s txHash is %s", route.Name, method, txHash.ToHexString())
+		result, err := tracker.Confirm(ctx, txHash)
+		if err != nil {
+			if err == ErrTxDropped {
+				txsFailedCounter.WithLabelValues(labels...).Inc()
+			}
+			route.setTxResult(record, err.Error())
+			return fmt.Errorf("confirm tx %s error: %s", txHash.ToHexString(), err)
+		}
+		if !result.Success {
+			txsFailedCounter.WithLabelValues(labels...).Inc()
+			route.setTxResult(record, "failed: "+result.Reason)
+			return fmt.Errorf("tx %s failed: %s", result.TxHash, result.Reason)
+		}
+		txsSucceededCounter.WithLabelValues(labels...).Inc()
+		route.setTxResult(record, "success")
+		return nil
+	})
 	if err != nil {
-		log.Errorf("waitForSideBlock error:%s", err)
+		return err
 	}
+	submissionHistogram.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	return nil
 }
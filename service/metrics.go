@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ontio/crossChainClient/log"
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	METRICS_NAMESPACE = "ccc"
+	//interval between two reports of the relayer account balances
+	BALANCE_INTERVAL = time.Minute
+	//ONG has 9 decimals
+	ONG_DECIMALS = 1e9
+)
+
+var (
+	sourceHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "source_height",
+		Help:      "Current block height of the source chain of a route.",
+	}, []string{"from", "to"})
+	processedHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "processed_height",
+		Help:      "Last block of the source chain of a route whose requests have all been relayed.",
+	}, []string{"from", "to"})
+	lagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "lag_blocks",
+		Help:      "Blocks of the source chain of a route waiting to be relayed.",
+	}, []string{"from", "to"})
+	headersSyncedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "headers_synced_total",
+		Help:      "Headers of the source chain of a route synced to the destination chain.",
+	}, []string{"from", "to"})
+	txsSubmittedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "txs_submitted_total",
+		Help:      "Transactions sent to the destination chain of a route, proofs have method processCrossChainTx.",
+	}, []string{"from", "to", "method"})
+	txsSucceededCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "txs_succeeded_total",
+		Help:      "Transactions executed successfully on the destination chain of a route.",
+	}, []string{"from", "to", "method"})
+	txsFailedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "txs_failed_total",
+		Help:      "Transactions failed or dropped on the destination chain of a route.",
+	}, []string{"from", "to", "method"})
+	processedMismatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "processed_mismatches_total",
+		Help:      "Requests relayed to the destination chain of a route but not read back as processed, the relayer then reads the wrong key of the cross_chain contract.",
+	}, []string{"from", "to"})
+	rpcErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "rpc_errors_total",
+		Help:      "Failed calls to a chain node by method.",
+	}, []string{"method"})
+	proofFetchHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "proof_fetch_seconds",
+		Help:      "Time to fetch the proof of a request from the source chain of a route, retries included.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"from", "to"})
+	submissionHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "submission_seconds",
+		Help:      "Time from building a transaction to its successful execution on the destination chain of a route, resubmissions included.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"from", "to", "method"})
+	balanceGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: METRICS_NAMESPACE,
+		Name:      "account_balance_ong",
+		Help:      "ONG balance of a relayer account on a chain.",
+	}, []string{"chain", "address"})
+)
+
+func init() {
+	prometheus.MustRegister(sourceHeightGauge, processedHeightGauge, lagGauge, headersSyncedCounter,
+		txsSubmittedCounter, txsSucceededCounter, txsFailedCounter, processedMismatchCounter, rpcErrorsCounter,
+		proofFetchHistogram, submissionHistogram, balanceGauge)
+}
+
+//metricLabels return the from and to labels of the metrics of route
+func (this *Route) metricLabels(extra ...string) []string {
+	return append([]string{fmt.Sprint(this.From.ChainID), fmt.Sprint(this.To.ChainID)}, extra...)
+}
+
+//reportHeights update the height gauges of route, with route.state.lock held
+func reportHeights(route *Route) {
+	labels := route.metricLabels()
+	sourceHeightGauge.WithLabelValues(labels...).Set(float64(route.state.current))
+	processedHeightGauge.WithLabelValues(labels...).Set(float64(route.syncHeight) - 1)
+	lagGauge.WithLabelValues(labels...).Set(float64(route.lag()))
+}
+
+//countRpcError count a failed call to a chain node
+func countRpcError(method string) {
+	rpcErrorsCounter.WithLabelValues(method).Inc()
+}
+
+//watchBalances report the ONG balance of the accounts of every chain every BALANCE_INTERVAL until
+//ctx is done
+func (this *SyncService) watchBalances(ctx context.Context) {
+	for {
+		this.reportBalances()
+		if !sleep(ctx, BALANCE_INTERVAL) {
+			return
+		}
+	}
+}
+
+func (this *SyncService) reportBalances() {
+	for _, chain := range this.chains {
+		addresses := []ocommon.Address{chain.Accounts.Header.Address()}
+		if proof := chain.Accounts.Proof.Address(); proof != addresses[0] {
+			addresses = append(addresses, proof)
+		}
+		for _, address := range addresses {
+			balance, err := chain.Client.GetOngBalance(address)
+			if err != nil {
+				countRpcError("GetOngBalance")
+				log.Warnf("[Chain %d] GetOngBalance of %s error:%s", chain.ChainID, address.ToBase58(), err)
+				continue
+			}
+			balanceGauge.WithLabelValues(fmt.Sprint(chain.ChainID), address.ToBase58()).
+				Set(float64(balance) / ONG_DECIMALS)
+		}
+	}
+}
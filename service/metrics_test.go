@@ -0,0 +1,105 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+//resetMetrics clear the global metrics so that the tests can run several times
+func resetMetrics() {
+	for _, vec := range []interface{ Reset() }{sourceHeightGauge, processedHeightGauge, lagGauge,
+		headersSyncedCounter, txsSubmittedCounter, txsSucceededCounter, txsFailedCounter, processedMismatchCounter,
+		rpcErrorsCounter, proofFetchHistogram, submissionHistogram, balanceGauge} {
+		vec.Reset()
+	}
+}
+
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	metric := &dto.Metric{}
+	assert.Nil(t, observer.(prometheus.Metric).Write(metric))
+	return metric.Histogram.GetSampleCount()
+}
+
+func TestRelayMetrics(t *testing.T) {
+	resetMetrics()
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	mainChain.FailNext("GetCrossStatesProof", 1)
+	sideChain.FailTx(cross_chain.PROCESS_CROSS_CHAIN_TX, "proof verify failed")
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+
+	proof := route.metricLabels(cross_chain.PROCESS_CROSS_CHAIN_TX)
+	header := route.metricLabels(header_sync.SYNC_BLOCK_HEADER)
+	assert.Equal(t, float64(1), testutil.ToFloat64(headersSyncedCounter.WithLabelValues(route.metricLabels()...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(txsSubmittedCounter.WithLabelValues(header...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(txsSucceededCounter.WithLabelValues(header...)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(txsSubmittedCounter.WithLabelValues(proof...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(txsSucceededCounter.WithLabelValues(proof...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(txsFailedCounter.WithLabelValues(proof...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(rpcErrorsCounter.WithLabelValues("GetCrossStatesProof")))
+	assert.Equal(t, uint64(1), histogramCount(t, proofFetchHistogram.WithLabelValues(route.metricLabels()...)))
+	assert.Equal(t, uint64(1), histogramCount(t, submissionHistogram.WithLabelValues(proof...)))
+}
+
+func TestReportHeights(t *testing.T) {
+	syncService, _, _, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testSideChainID, testMainChainID)
+	labels := route.metricLabels()
+
+	route.syncHeight = 5
+	route.observe(12)
+	assert.Equal(t, float64(12), testutil.ToFloat64(sourceHeightGauge.WithLabelValues(labels...)))
+	assert.Equal(t, float64(4), testutil.ToFloat64(processedHeightGauge.WithLabelValues(labels...)))
+	assert.Equal(t, float64(7), testutil.ToFloat64(lagGauge.WithLabelValues(labels...)))
+
+	route.setSyncHeight(11)
+	route.advance(11)
+	assert.Equal(t, float64(11), testutil.ToFloat64(processedHeightGauge.WithLabelValues(labels...)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(lagGauge.WithLabelValues(labels...)))
+}
+
+func TestReportBalances(t *testing.T) {
+	resetMetrics()
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	accounts := testAccounts(testMainChainID)
+	mainChain.SetOngBalance(accounts.Header.Address(), 1500000000)
+	mainChain.SetOngBalance(accounts.Proof.Address(), 2)
+	sideChain.FailNext("GetOngBalance", 2)
+	syncService.reportBalances()
+
+	header, proof := accounts.Header.Address(), accounts.Proof.Address()
+	assert.Equal(t, 1.5, testutil.ToFloat64(balanceGauge.WithLabelValues("0", header.ToBase58())))
+	assert.Equal(t, 2e-9, testutil.ToFloat64(balanceGauge.WithLabelValues("0", proof.ToBase58())))
+	assert.Equal(t, float64(2), testutil.ToFloat64(rpcErrorsCounter.WithLabelValues("GetOngBalance")))
+}
+
+func TestProcessedMismatchMetric(t *testing.T) {
+	resetMetrics()
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, float64(0), testutil.ToFloat64(processedMismatchCounter.WithLabelValues(route.metricLabels()...)))
+
+	sideChain.UseOtherLayout()
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height+1))
+	assert.Equal(t, 2, len(sideChain.Proofs()))
+	assert.Equal(t, float64(1), testutil.ToFloat64(processedMismatchCounter.WithLabelValues(route.metricLabels()...)))
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ontio/crossChainClient/config"
+	"github.com/ontio/crossChainClient/log"
+)
+
+//reloadable fields of config.Config, the others need a restart
+var reloadableFields = map[string]bool{
+	"GasPrice":         true,
+	"GasLimit":         true,
+	"LogLevel":         true,
+	"PollingInterval":  true,
+	"ConfirmTimeout":   true,
+	"RetryMaxAttempts": true,
+	"RetryBaseDelay":   true,
+	"RetryMaxDelay":    true,
+	"RetryJitter":      true,
+	"ReadyMaxLag":      true,
+	"ReadyTimeout":     true,
+}
+
+//reloadable fields of config.ChainConfig
+var reloadableChainFields = map[string]bool{
+	"GasPrice": true,
+	"GasLimit": true,
+}
+
+//ConfigChange is a field of the config changed by a reload
+type ConfigChange struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+func (this *ConfigChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", this.Field, this.Old, this.New)
+}
+
+//Reload apply to the running service the fields of cfg which are safe to change: the gas settings,
+//log level, polling interval, confirm timeout and retry policy. The new config replaces the current
+//one at once, so that each operation sees either the old or the new values. It return the changes
+//applied and the changes rejected because they need a restart, such as the chain ids
+func (this *SyncService) Reload(cfg *config.Config) (applied, rejected []*ConfigChange) {
+	this.reloadLock.Lock()
+	defer this.reloadLock.Unlock()
+	return this.reloadLocked(cfg)
+}
+
+//reloadLocked is Reload with reloadLock held
+func (this *SyncService) reloadLocked(cfg *config.Config) (applied, rejected []*ConfigChange) {
+	current := this.GetConfig()
+	next := *current
+	next.Chains = make([]*config.ChainConfig, 0, len(current.Chains))
+	for _, chain := range current.Chains {
+		chainCopy := *chain
+		next.Chains = append(next.Chains, &chainCopy)
+	}
+
+	oldValue, newValue := reflect.ValueOf(current).Elem(), reflect.ValueOf(cfg).Elem()
+	nextValue := reflect.ValueOf(&next).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		name := oldValue.Type().Field(i).Name
+		if name == "Chains" {
+			continue
+		}
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			continue
+		}
+		change := &ConfigChange{Field: name, Old: oldField.Interface(), New: newField.Interface()}
+		if reloadableFields[name] {
+			nextValue.Field(i).Set(newField)
+			applied = append(applied, change)
+		} else {
+			rejected = append(rejected, change)
+		}
+	}
+
+	if len(current.Chains) != len(cfg.Chains) {
+		rejected = append(rejected, &ConfigChange{Field: "Chains", Old: len(current.Chains), New: len(cfg.Chains)})
+	} else {
+		for i, chain := range cfg.Chains {
+			chainApplied, chainRejected := diffChain(current.Chains[i], chain, next.Chains[i])
+			applied = append(applied, chainApplied...)
+			rejected = append(rejected, chainRejected...)
+		}
+	}
+
+	if len(applied) == 0 {
+		return
+	}
+	if next.LogLevel != current.LogLevel {
+		if err := log.Log.SetDebugLevel(next.LogLevel); err != nil {
+			log.Warnf("[Reload] SetDebugLevel %d error:%s", next.LogLevel, err)
+		}
+	}
+	this.config.Store(&next)
+	return
+}
+
+//diffChain compare oldChain with newChain, applying the reloadable changes to nextChain
+func diffChain(oldChain, newChain, nextChain *config.ChainConfig) (applied, rejected []*ConfigChange) {
+	oldValue, newValue := reflect.ValueOf(oldChain).Elem(), reflect.ValueOf(newChain).Elem()
+	nextValue := reflect.ValueOf(nextChain).Elem()
+	for i := 0; i < oldValue.NumField(); i++ {
+		name := oldValue.Type().Field(i).Name
+		oldField, newField := oldValue.Field(i), newValue.Field(i)
+		if reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			continue
+		}
+		change := &ConfigChange{
+			Field: fmt.Sprintf("Chains[%d].%s", oldChain.ChainID, name),
+			Old:   oldField.Interface(),
+			New:   newField.Interface(),
+		}
+		if reloadableChainFields[name] && oldChain.ChainID == newChain.ChainID {
+			nextValue.Field(i).Set(newField)
+			applied = append(applied, change)
+		} else {
+			rejected = append(rejected, change)
+		}
+	}
+	return
+}
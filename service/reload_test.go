@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/crossChainClient/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	current := syncService.GetConfig()
+
+	cfg := *current
+	cfg.Chains = []*config.ChainConfig{
+		{ChainID: testMainChainID, GasLimit: 200000},
+		{ChainID: testSideChainID, GasPrice: 500, GasLimit: 300000, WsAddress: "ws://127.0.0.1:30335"},
+	}
+	cfg.PollingInterval = 20
+	cfg.RetryMaxAttempts = 4
+	cfg.MainChainID = 5
+	applied, rejected := syncService.Reload(&cfg)
+	assert.Equal(t, []*ConfigChange{
+		{Field: "PollingInterval", Old: uint64(10), New: uint64(20)},
+		{Field: "RetryMaxAttempts", Old: uint64(3), New: uint64(4)},
+		{Field: "Chains[1].GasPrice", Old: uint64(0), New: uint64(500)},
+		{Field: "Chains[1].GasLimit", Old: uint64(200000), New: uint64(300000)},
+	}, applied)
+	assert.Equal(t, []*ConfigChange{
+		{Field: "MainChainID", Old: testMainChainID, New: uint64(5)},
+		{Field: "Chains[1].WsAddress", Old: "", New: "ws://127.0.0.1:30335"},
+	}, rejected)
+
+	assert.Equal(t, 20*time.Millisecond, syncService.GetPollingInterval())
+	assert.Equal(t, uint64(4), syncService.GetRetryPolicy().MaxAttempts)
+	assert.Equal(t, testMainChainID, syncService.GetMainChainID())
+	assert.Equal(t, "", syncService.GetConfig().GetChain(testSideChainID).WsAddress)
+	//the previous config is left untouched
+	assert.Equal(t, uint64(10), current.PollingInterval)
+	assert.Equal(t, uint64(200000), current.Chains[1].GasLimit)
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	txs := sideChain.Txs()
+	assert.Equal(t, 2, len(txs))
+	for _, tx := range txs {
+		assert.Equal(t, uint64(500), tx.GasPrice)
+		assert.Equal(t, uint64(300000), tx.GasLimit)
+	}
+
+	applied, rejected = syncService.Reload(syncService.GetConfig())
+	assert.Equal(t, 0, len(applied))
+	assert.Equal(t, 0, len(rejected))
+}
+
+func TestReloadConcurrentSetLogLevel(t *testing.T) {
+	syncService, _, _, cleanup := newTestService(t)
+	defer cleanup()
+	level := syncService.GetConfig().LogLevel
+	defer syncService.SetLogLevel(level)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			syncService.SetLogLevel(1)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		cfg := *syncService.GetConfig()
+		cfg.LogLevel = 1
+		cfg.PollingInterval = uint64(20 + i)
+		syncService.Reload(&cfg)
+	}
+	<-done
+	//SetLogLevel does not put back a polling interval read before a reload
+	assert.Equal(t, 1, syncService.GetConfig().LogLevel)
+	assert.Equal(t, 119*time.Millisecond, syncService.GetPollingInterval())
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/ontio/crossChainClient/log"
+)
+
+//RetryPolicy retry a failing call with exponential backoff and jitter
+type RetryPolicy struct {
+	//attempts before giving up, 0 and 1 both mean a single attempt
+	MaxAttempts uint64
+	//delay before the second attempt, doubled for each following one
+	BaseDelay time.Duration
+	//upper bound of the delay before jitter, no bound if 0
+	MaxDelay time.Duration
+	//fraction of the delay randomly added or removed, in [0, 1]
+	Jitter float64
+}
+
+//Delay return the backoff to wait after the failed attempt number attempt (starting from 1)
+func (this *RetryPolicy) Delay(attempt uint64) time.Duration {
+	delay := this.BaseDelay
+	for i := uint64(1); i < attempt; i++ {
+		if (this.MaxDelay != 0 && delay >= this.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if this.MaxDelay != 0 && delay > this.MaxDelay {
+		delay = this.MaxDelay
+	}
+	if this.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * this.Jitter * float64(delay))
+	}
+	return delay
+}
+
+//Do call fn until it succeeds, MaxAttempts is reached or ctx is done, and return the last error, or
+//the ctx error if ctx is done before the first attempt
+func (this *RetryPolicy) Do(ctx context.Context, name string, fn func() error) error {
+	for attempt := uint64(1); ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt >= this.MaxAttempts {
+			return err
+		}
+		delay := this.Delay(attempt)
+		log.Warnf("%s attempt %d/%d error:%s, retry in %s", name, attempt, this.MaxAttempts, err, delay)
+		if !sleep(ctx, delay) {
+			return err
+		}
+	}
+}
+
+//sleep wait for d, it return false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Delay(2))
+	assert.Equal(t, 800*time.Millisecond, policy.Delay(4))
+	assert.Equal(t, time.Second, policy.Delay(5))
+	assert.Equal(t, time.Second, policy.Delay(1000))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(2)
+		assert.True(t, delay >= 100*time.Millisecond && delay <= 300*time.Millisecond, delay.String())
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	calls := 0
+	err := policy.Do(context.Background(), "test", func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("failure %d", calls)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = policy.Do(context.Background(), "test", func() error {
+		calls++
+		return fmt.Errorf("failure %d", calls)
+	})
+	assert.Equal(t, "failure 3", err.Error())
+	assert.Equal(t, 3, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	policy.BaseDelay = time.Hour
+	err = policy.Do(ctx, "test", func() error {
+		calls++
+		return fmt.Errorf("failure %d", calls)
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestFailureDelay(t *testing.T) {
+	syncService, _, _, cleanup := newTestService(t)
+	defer cleanup()
+	assert.Equal(t, 10*time.Millisecond, syncService.failureDelay(1000))
+
+	cfg := *syncService.GetConfig()
+	cfg.RetryBaseDelay = 1000
+	cfg.RetryMaxDelay = 0
+	syncService.config.Store(&cfg)
+	assert.Equal(t, time.Second, syncService.failureDelay(1))
+	assert.Equal(t, MAX_FAILURE_DELAY, syncService.failureDelay(100))
+	assert.Equal(t, MAX_FAILURE_DELAY, syncService.failureDelay(1<<40))
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//number of recent txs kept per route for the status
+const MAX_ROUTE_TXS = 10
+
+//RouteTx is a tx recently submitted to the destination chain of a route
+type RouteTx struct {
+	Method string
+	TxHash string
+	Time   time.Time
+	//pending, success, or why it failed
+	Result string
+}
+
+//RouteStatus is the state of a route reported by the admin api
+type RouteStatus struct {
+	Name         string
+	FromChainID  uint64
+	ToChainID    uint64
+	Running      bool
+	Paused       bool
+	SourceHeight uint32
+	NextHeight   uint32
+	//blocks waiting to be relayed
+	Lag         uint32
+	LastSuccess time.Time
+	LastError   string `json:",omitempty"`
+	//oldest first
+	LastTxs []RouteTx
+}
+
+//routeState is the progress of a route reported by its relay loop and the controls set by the
+//admin api. Its lock also guards Route.syncHeight
+type routeState struct {
+	lock    sync.RWMutex
+	running bool
+	paused  bool
+	//height of the source chain
+	current uint32
+	//last time a block was relayed or the route was caught up
+	lastSuccess time.Time
+	lastError   string
+	//recent txs, oldest first
+	txs []*RouteTx
+}
+
+//setRunning record whether the relay loop of the route is running
+func (this *Route) setRunning(running bool) {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	this.state.running = running
+	if running {
+		this.state.lastSuccess = time.Now()
+	}
+}
+
+func (this *Route) getSyncHeight() uint32 {
+	this.state.lock.RLock()
+	defer this.state.lock.RUnlock()
+	return this.syncHeight
+}
+
+func (this *Route) setSyncHeight(height uint32) {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	this.syncHeight = height
+}
+
+//lag return the blocks waiting to be relayed, with state.lock held
+func (this *Route) lag() uint32 {
+	if this.state.current > this.syncHeight {
+		return this.state.current - this.syncHeight
+	}
+	return 0
+}
+
+//observe record current, the height of route.From. Being caught up counts as a success
+func (this *Route) observe(current uint32) {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	this.state.current = current
+	if this.syncHeight >= current {
+		this.state.lastSuccess = time.Now()
+		this.state.lastError = ""
+	}
+	reportHeights(this)
+}
+
+//next return the next height to relay, false if the route is paused or caught up
+func (this *Route) next() (uint32, bool) {
+	this.state.lock.RLock()
+	defer this.state.lock.RUnlock()
+	return this.syncHeight, !this.state.paused && this.syncHeight < this.state.current
+}
+
+//advance move past height once it is relayed or skipped, it return false if height is not the next
+//height, which happens when it was skipped while being relayed. The check and the move are atomic
+func (this *Route) advance(height uint32) bool {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	if this.syncHeight != height {
+		return false
+	}
+	this.syncHeight++
+	this.state.lastSuccess = time.Now()
+	this.state.lastError = ""
+	reportHeights(this)
+	return true
+}
+
+//fail record the error relaying the next height
+func (this *Route) fail(err error) {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	this.state.lastError = err.Error()
+}
+
+func (this *Route) setPaused(paused bool) {
+	this.state.lock.Lock()
+	this.state.paused = paused
+	this.state.lock.Unlock()
+	this.notify()
+}
+
+//notify wake the relay loop up to apply a control change
+func (this *Route) notify() {
+	select {
+	case this.wake <- struct{}{}:
+	default:
+	}
+}
+
+//wait for d, until ctx is done or the relay loop is woken up
+func (this *Route) wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	case <-this.wake:
+	}
+}
+
+//recordTx add a tx submitted to route.To to the recent txs, its result is pending until setTxResult
+func (this *Route) recordTx(method, txHash string) *RouteTx {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	tx := &RouteTx{Method: method, TxHash: txHash, Time: time.Now(), Result: "pending"}
+	this.state.txs = append(this.state.txs, tx)
+	if len(this.state.txs) > MAX_ROUTE_TXS {
+		this.state.txs = this.state.txs[len(this.state.txs)-MAX_ROUTE_TXS:]
+	}
+	return tx
+}
+
+func (this *Route) setTxResult(tx *RouteTx, result string) {
+	this.state.lock.Lock()
+	defer this.state.lock.Unlock()
+	tx.Result = result
+}
+
+//Status return a snapshot of the state of the route
+func (this *Route) Status() *RouteStatus {
+	this.state.lock.RLock()
+	defer this.state.lock.RUnlock()
+	status := &RouteStatus{
+		Name:         this.Name,
+		FromChainID:  this.From.ChainID,
+		ToChainID:    this.To.ChainID,
+		Running:      this.state.running,
+		Paused:       this.state.paused,
+		SourceHeight: this.state.current,
+		NextHeight:   this.syncHeight,
+		Lag:          this.lag(),
+		LastSuccess:  this.state.lastSuccess,
+		LastError:    this.state.lastError,
+		LastTxs:      make([]RouteTx, 0, len(this.state.txs)),
+	}
+	for _, tx := range this.state.txs {
+		status.LastTxs = append(status.LastTxs, *tx)
+	}
+	return status
+}
@@ -1,159 +1,374 @@
 package service
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 
-	"encoding/json"
+	"github.com/ontio/crossChainClient/checkpoint"
 	"github.com/ontio/crossChainClient/config"
 	"github.com/ontio/crossChainClient/log"
-	sdk "github.com/ontio/ontology-go-sdk"
-	"github.com/ontio/ontology/consensus/vbft/config"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
 )
 
+//ChainAccounts are the accounts signing the txs submitted to a chain
+type ChainAccounts struct {
+	Header Signer
+	Proof  Signer
+}
+
+//Chain is a configured chain, the client connected to it and the accounts submitting txs to it
+type Chain struct {
+	*config.ChainConfig
+	Client   ChainClient
+	Accounts *ChainAccounts
+}
+
+//Route relay the key headers and cross chain requests of chain From to chain To
+type Route struct {
+	Name string
+	From *Chain
+	To   *Chain
+	//next height of From to relay, guarded by state.lock
+	syncHeight uint32
+	state      routeState
+	//wake the relay loop up after a control change
+	wake chan struct{}
+	//serialize the checkpoint writes of the route with the moves of syncHeight, so that a block
+	//skipped by the admin api and a block relayed meanwhile never put the checkpoint back
+	checkpointLock sync.Mutex
+}
+
 type SyncService struct {
-	account        *sdk.Account
-	mainSdk        *sdk.OntologySdk
-	mainSyncHeight uint32
-	sideSdk        *sdk.OntologySdk
-	sideSyncHeight uint32
-	config         *config.Config
+	chains map[uint64]*Chain
+	routes []*Route
+	store  *checkpoint.Store
+	//current *config.Config, replaced as a whole by Reload
+	config atomic.Value
+	//serialize the config updates, so that none of them is based on a stale config
+	reloadLock sync.Mutex
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
 }
 
-func NewSyncService(acct *sdk.Account, mainSdk *sdk.OntologySdk, sideSdk *sdk.OntologySdk) *SyncService {
+//NewSyncService create the routes of config.DefConfig between the chains of clients, signing with
+//accounts. Both are indexed by chain id. store may be nil for a service only reading the chains, see
+//SyncStatus
+func NewSyncService(clients map[uint64]ChainClient, accounts map[uint64]*ChainAccounts, store *checkpoint.Store) (*SyncService, error) {
+	return newSyncService(clients, accounts, store, config.DefConfig)
+}
+
+func newSyncService(clients map[uint64]ChainClient, accounts map[uint64]*ChainAccounts, store *checkpoint.Store,
+	cfg *config.Config) (*SyncService, error) {
 	syncSvr := &SyncService{
-		account: acct,
-		mainSdk: mainSdk,
-		sideSdk: sideSdk,
-		config:  config.DefConfig,
+		chains: make(map[uint64]*Chain),
+		store:  store,
+	}
+	syncSvr.config.Store(cfg)
+	for _, chainConfig := range cfg.GetChains() {
+		client, ok := clients[chainConfig.ChainID]
+		if !ok {
+			return nil, fmt.Errorf("no client for chain %d", chainConfig.ChainID)
+		}
+		chainAccounts, ok := accounts[chainConfig.ChainID]
+		if !ok || chainAccounts.Header == nil || chainAccounts.Proof == nil {
+			return nil, fmt.Errorf("no accounts for chain %d", chainConfig.ChainID)
+		}
+		syncSvr.chains[chainConfig.ChainID] = &Chain{ChainConfig: chainConfig, Client: client, Accounts: chainAccounts}
 	}
-	return syncSvr
+	for _, routeConfig := range cfg.GetRoutes() {
+		from, ok := syncSvr.chains[routeConfig.FromChainID]
+		if !ok {
+			return nil, fmt.Errorf("route from unknown chain %d", routeConfig.FromChainID)
+		}
+		to, ok := syncSvr.chains[routeConfig.ToChainID]
+		if !ok {
+			return nil, fmt.Errorf("route to unknown chain %d", routeConfig.ToChainID)
+		}
+		syncSvr.routes = append(syncSvr.routes, &Route{
+			Name: fmt.Sprintf("%d->%d", from.ChainID, to.ChainID),
+			From: from,
+			To:   to,
+			wake: make(chan struct{}, 1),
+		})
+	}
+	return syncSvr, nil
 }
 
-func (this *SyncService) Run() {
-	go this.MainToSide()
-	go this.SideToMain()
+//GetRoute return the route from chain fromChainID to chain toChainID, nil if not configured
+func (this *SyncService) GetRoute(fromChainID, toChainID uint64) *Route {
+	for _, route := range this.routes {
+		if route.From.ChainID == fromChainID && route.To.ChainID == toChainID {
+			return route
+		}
+	}
+	return nil
 }
 
-func (this *SyncService) MainToSide() {
-	currentSideChainSyncHeight, err := this.GetCurrentSideChainSyncHeight(this.GetMainChainID())
-	if err != nil {
-		log.Errorf("[MainToSide] this.GetCurrentSideChainSyncHeight error:", err)
-		os.Exit(1)
+//Run start relaying every route until ctx is done or Stop is called. Routes from the same chain
+//share the block source of that chain. The account balances are reported to the metrics meanwhile
+func (this *SyncService) Run(ctx context.Context) {
+	ctx, this.cancel = context.WithCancel(ctx)
+	sources := make(map[uint64]*SharedBlockSource)
+	for _, route := range this.routes {
+		source, ok := sources[route.From.ChainID]
+		if !ok {
+			source = NewSharedBlockSource(NewBlockSource(ctx, fmt.Sprintf("Chain %d", route.From.ChainID),
+				route.From.Client, route.From.WsAddress, this.GetPollingInterval))
+			sources[route.From.ChainID] = source
+		}
+		heights := source.Subscribe()
+		route.setRunning(true)
+		this.wg.Add(1)
+		go func(route *Route) {
+			defer this.wg.Done()
+			defer route.setRunning(false)
+			this.relay(ctx, route, heights)
+		}(route)
+	}
+	for _, source := range sources {
+		source.Start()
+	}
+	this.wg.Add(1)
+	go func() {
+		defer this.wg.Done()
+		this.watchBalances(ctx)
+	}()
+}
+
+//Stop ask every route to exit once the block in flight is relayed, which may take the shutdown grace,
+//and wait for them
+func (this *SyncService) Stop() {
+	if this.cancel != nil {
+		this.cancel()
+	}
+	this.wg.Wait()
+}
+
+//relay the blocks of route.From published by source until it is closed, unless the route is paused
+func (this *SyncService) relay(ctx context.Context, route *Route, source BlockSource) {
+	startHeight, ok := this.waitStartHeight(ctx, route)
+	if !ok {
+		return
 	}
-	this.sideSyncHeight = currentSideChainSyncHeight
+	route.setSyncHeight(startHeight)
+	failures := uint64(0)
+	heights := source.Heights()
 	for {
-		currentMainChainHeight, err := this.mainSdk.GetCurrentBlockHeight()
-		if err != nil {
-			log.Errorf("[MainToSide] this.mainSdk.GetCurrentBlockHeight error:", err)
-		}
-		for i := this.sideSyncHeight; i < currentMainChainHeight; i++ {
-			log.Infof("[MainToSide] start parse block %d", i)
-			//sync key header
-			block, err := this.mainSdk.GetBlockByHeight(i)
-			if err != nil {
-				log.Errorf("[MainToSide] this.mainSdk.GetBlockByHeight error:", err)
-			}
-			blkInfo := &vconfig.VbftBlockInfo{}
-			if err := json.Unmarshal(block.Header.ConsensusPayload, blkInfo); err != nil {
-				log.Errorf("[MainToSide] unmarshal blockInfo error: %s", err)
-			}
-			if blkInfo.NewChainConfig != nil {
-				err = this.syncHeaderToSide(i)
-				if err != nil {
-					log.Errorf("[MainToSide] this.syncHeaderToSide error:%s", err)
-				}
+		select {
+		case currentHeight, ok := <-heights:
+			if !ok {
+				log.Infof("[%s] stopped, next height %d", route.Name, route.getSyncHeight())
+				return
 			}
-
-			//sync cross chain info
-			events, err := this.mainSdk.GetSmartContractEventByBlock(i)
-			if err != nil {
-				log.Errorf("[MainToSide] this.mainSdk.GetSmartContractEventByBlock error:%s", err)
+			route.observe(currentHeight)
+		case <-route.wake:
+		}
+		for ctx.Err() == nil {
+			height, ok := route.next()
+			if !ok {
 				break
 			}
-			for _, event := range events {
-				for _, notify := range event.Notify {
-					states, ok := notify.States.([]interface{})
-					if !ok {
-						continue
-					}
-					name := states[0].(string)
-					if name == cross_chain.CREATE_CROSS_CHAIN_TX {
-						requestID := uint64(states[2].(float64))
-						err = this.syncHeaderToSide(i + 1)
-						if err != nil {
-							log.Errorf("[MainToSide] this.syncHeaderToSide error:%s", err)
-						}
-						err = this.sendProofToSide(requestID, i)
-						if err != nil {
-							log.Errorf("[MainToSide] this.sendProofToSide error:%s", err)
-						}
-					}
-				}
+			//a shutdown lets the block in flight finish, for at most the grace
+			blockCtx, cancel := detach(ctx, this.GetShutdownGrace())
+			err := this.relayBlock(blockCtx, route, height)
+			cancel()
+			if err != nil {
+				route.fail(err)
+				//the block is processed again, skipping the requests already relayed
+				failures++
+				delay := this.failureDelay(failures)
+				log.Errorf("[%s] relay block %d error:%s, retry in %s", route.Name, height, err, delay)
+				route.wait(ctx, delay)
+				continue
 			}
-			this.sideSyncHeight++
+			failures = 0
+		}
+	}
+}
+
+//waitStartHeight call getStartHeight until it succeeds, reporting the route failed meanwhile without
+//holding up the other routes. It return false if ctx is done first
+func (this *SyncService) waitStartHeight(ctx context.Context, route *Route) (uint32, bool) {
+	for failures := uint64(1); ; failures++ {
+		height, err := this.getStartHeight(ctx, route)
+		if err == nil {
+			return height, true
+		}
+		if ctx.Err() != nil {
+			log.Infof("[%s] stopped before starting", route.Name)
+			return 0, false
+		}
+		route.fail(err)
+		delay := this.failureDelay(failures)
+		log.Errorf("[%s] this.getStartHeight error:%s, retry in %s", route.Name, err, delay)
+		if !sleep(ctx, delay) {
+			log.Infof("[%s] stopped before starting", route.Name)
+			return 0, false
 		}
 	}
 }
 
-func (this *SyncService) SideToMain() {
-	currentMainChainSyncHeight, err := this.GetCurrentMainChainSyncHeight(this.GetSideChainID())
+//getStartHeight resume after the last checkpointed block of route, or from the header sync height
+//on the destination chain if nothing was checkpointed yet
+func (this *SyncService) getStartHeight(ctx context.Context, route *Route) (uint32, error) {
+	height, ok, err := this.store.GetHeight(route.From.ChainID, route.To.ChainID)
 	if err != nil {
-		log.Errorf("[SideToMain] this.GetCurrentMainChainSyncHeight error:", err)
-		os.Exit(1)
+		return 0, fmt.Errorf("this.store.GetHeight error:%s", err)
 	}
-	this.mainSyncHeight = currentMainChainSyncHeight
-	for {
-		currentSideChainHeight, err := this.sideSdk.GetCurrentBlockHeight()
+	if ok {
+		return height + 1, nil
+	}
+	return this.GetSyncHeight(ctx, route.To, route.From.ChainID)
+}
+
+//relayBlock parse block height of route.From and relay its key header and the cross chain requests
+//to route.To, then checkpoint it. It return an error unless every request of the block has been relayed
+func (this *SyncService) relayBlock(ctx context.Context, route *Route, height uint32) error {
+	log.Infof("[%s] start parse block %d", route.Name, height)
+	_, err := this.relayRequests(ctx, route, height, false, nil)
+	if err != nil {
+		return err
+	}
+	return this.checkpoint(route, height)
+}
+
+//checkpoint persist that block height of route is relayed and move the route past it, unless the
+//route already moved past it because the block was skipped while being relayed
+func (this *SyncService) checkpoint(route *Route, height uint32) error {
+	route.checkpointLock.Lock()
+	defer route.checkpointLock.Unlock()
+	if next := route.getSyncHeight(); next > height {
+		log.Infof("[%s] block %d skipped while relayed, next height %d", route.Name, height, next)
+		return nil
+	}
+	err := this.store.PutHeight(route.From.ChainID, route.To.ChainID, height)
+	if err != nil {
+		return fmt.Errorf("this.store.PutHeight error:%s", err)
+	}
+	route.advance(height)
+	return nil
+}
+
+//RelayRequests relay the key header of block height of route.From and the requests it created for
+//route.To, or only the ones in requestIDs if it is not empty, whether or not the checkpoint store
+//marks them relayed. It is meant for one-shot relays while the service is not running, Rerelay being
+//the equivalent for a running service. It return the ids of the requests relayed
+func (this *SyncService) RelayRequests(ctx context.Context, route *Route, height uint32, requestIDs []uint64) ([]uint64, error) {
+	return this.relayRequests(ctx, route, height, true, requestIDs)
+}
+
+//relayRequests relay the key header of block height of route.From and the cross chain requests it
+//created for route.To. With force the requests marked relayed in the checkpoint store are relayed
+//again, and only the ones in requestIDs if it is not empty. It return the ids of the requests relayed
+//and an error unless all of them have been relayed
+func (this *SyncService) relayRequests(ctx context.Context, route *Route, height uint32, force bool,
+	requestIDs []uint64) ([]uint64, error) {
+	//sync key header
+	var block *types.Block
+	err := this.retry(ctx, "GetBlockByHeight", func() (err error) {
+		block, err = route.From.Client.GetBlockByHeight(height)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockByHeight error:%s", err)
+	}
+	key, err := isKeyHeader(block.Header)
+	if err != nil {
+		return nil, err
+	}
+	if key {
+		err = this.syncHeader(ctx, route, height)
 		if err != nil {
-			log.Errorf("[SideToMain] this.sideSdk.GetCurrentBlockHeight error:", err)
+			return nil, fmt.Errorf("this.syncHeader error:%s", err)
 		}
-		for i := this.mainSyncHeight; i < currentSideChainHeight; i++ {
-			log.Infof("[SideToMain] start parse block %d", i)
-			//sync key header
-			block, err := this.sideSdk.GetBlockByHeight(i)
-			if err != nil {
-				log.Errorf("[SideToMain] this.mainSdk.GetBlockByHeight error:", err)
+	}
+
+	//sync cross chain info
+	found, err := this.findRequests(ctx, route, height)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[uint64]bool)
+	for _, requestID := range requestIDs {
+		wanted[requestID] = true
+	}
+	relayed := make([]uint64, 0)
+	failed := 0
+	for _, requestID := range found {
+		if len(wanted) != 0 {
+			if !wanted[requestID] {
+				continue
 			}
-			blkInfo := &vconfig.VbftBlockInfo{}
-			if err := json.Unmarshal(block.Header.ConsensusPayload, blkInfo); err != nil {
-				log.Errorf("[SideToMain] unmarshal blockInfo error: %s", err)
+			delete(wanted, requestID)
+		}
+		if !force {
+			done, err := this.store.IsRelayed(route.From.ChainID, route.To.ChainID, requestID)
+			if err != nil {
+				return relayed, fmt.Errorf("this.store.IsRelayed error:%s", err)
 			}
-			if blkInfo.NewChainConfig != nil {
-				err = this.syncHeaderToMain(i)
-				if err != nil {
-					log.Errorf("[SideToMain] this.syncHeaderToMain error:%s", err)
-				}
+			if done {
+				log.Infof("[%s] request %d already relayed, skip", route.Name, requestID)
+				continue
 			}
+		}
+		err = this.syncHeader(ctx, route, height+1)
+		if err != nil {
+			log.Errorf("[%s] request %d this.syncHeader error:%s", route.Name, requestID, err)
+			failed++
+			continue
+		}
+		err = this.sendProof(ctx, route, requestID, height)
+		if err != nil {
+			log.Errorf("[%s] request %d this.sendProof error:%s", route.Name, requestID, err)
+			failed++
+			continue
+		}
+		relayed = append(relayed, requestID)
+		err = this.store.MarkRelayed(route.From.ChainID, route.To.ChainID, requestID)
+		if err != nil {
+			log.Errorf("[%s] this.store.MarkRelayed error:%s", route.Name, err)
+		}
+	}
+	if failed != 0 {
+		return relayed, fmt.Errorf("%d requests not relayed", failed)
+	}
+	if len(wanted) != 0 {
+		return relayed, fmt.Errorf("%d requests not found in block %d", len(wanted), height)
+	}
+	return relayed, nil
+}
 
-			//sync cross chain info
-			events, err := this.sideSdk.GetSmartContractEventByBlock(i)
+//findRequests return the ids of the cross chain requests for route.To created by block height of
+//route.From
+func (this *SyncService) findRequests(ctx context.Context, route *Route, height uint32) ([]uint64, error) {
+	var events []*sdkcom.SmartContactEvent
+	err := this.retry(ctx, "GetSmartContractEventByBlock", func() (err error) {
+		events, err = route.From.Client.GetSmartContractEventByBlock(height)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetSmartContractEventByBlock error:%s", err)
+	}
+	requestIDs := make([]uint64, 0)
+	for _, event := range events {
+		for _, notify := range event.Notify {
+			crossChainEvent, err := DecodeCrossChainEvent(notify)
+			if err == ErrNotCrossChainTx {
+				continue
+			}
 			if err != nil {
-				log.Errorf("[SideToMain] this.sideSdk.GetSmartContractEventByBlock error:%s", err)
-				break
+				log.Errorf("[%s] tx %s DecodeCrossChainEvent error:%s", route.Name, event.TxHash, err)
+				continue
 			}
-			for _, event := range events {
-				for _, notify := range event.Notify {
-					states, ok := notify.States.([]interface{})
-					if !ok {
-						continue
-					}
-					name := states[0].(string)
-					if name == cross_chain.CREATE_CROSS_CHAIN_TX {
-						requestID := uint64(states[2].(float64))
-						err = this.syncHeaderToMain(i + 1)
-						if err != nil {
-							log.Errorf("[SideToMain] this.syncHeaderToMain error:%s", err)
-						}
-						err = this.sendProofToMain(requestID, i)
-						if err != nil {
-							log.Errorf("[SideToMain] this.sendProofToMain error:%s", err)
-						}
-					}
-				}
+			if crossChainEvent.Name != cross_chain.CREATE_CROSS_CHAIN_TX || crossChainEvent.ChainID != route.To.ChainID {
+				continue
 			}
-			this.mainSyncHeight++
+			requestIDs = append(requestIDs, crossChainEvent.RequestID)
 		}
 	}
-
+	return requestIDs, nil
 }
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ontio/crossChainClient/checkpoint"
+	"github.com/ontio/crossChainClient/config"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMainChainID = uint64(0)
+	testSideChainID = uint64(1)
+)
+
+func newTestService(t *testing.T) (*SyncService, *simChain, *simChain, func()) {
+	mainChain, sideChain := newSimChainPair(testMainChainID, testSideChainID)
+	syncService, cleanup := newTestServiceWithChains(t, mainChain, sideChain)
+	return syncService, mainChain, sideChain, cleanup
+}
+
+//newTestServiceWithChains create a service relaying between mainChain and every side chain
+func newTestServiceWithChains(t *testing.T, mainChain *simChain, sideChains ...*simChain) (*SyncService, func()) {
+	dir, err := ioutil.TempDir("", "service")
+	assert.Nil(t, err)
+	store, err := checkpoint.NewStore(dir)
+	assert.Nil(t, err)
+	cfg := &config.Config{
+		MainChainID:      mainChain.chainID,
+		PollingInterval:  10,
+		RetryMaxAttempts: 3,
+		RetryBaseDelay:   1,
+		RetryMaxDelay:    10,
+		ConfirmTimeout:   100,
+	}
+	clients := make(map[uint64]ChainClient)
+	accounts := make(map[uint64]*ChainAccounts)
+	for _, chain := range append([]*simChain{mainChain}, sideChains...) {
+		cfg.Chains = append(cfg.Chains, &config.ChainConfig{ChainID: chain.chainID, GasLimit: 200000})
+		clients[chain.chainID] = chain
+		accounts[chain.chainID] = testAccounts(chain.chainID)
+	}
+	syncService, err := newSyncService(clients, accounts, store, cfg)
+	assert.Nil(t, err)
+	return syncService, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+//testSigner sign txs with a fake signature of its address
+type testSigner ocommon.Address
+
+func (this testSigner) Address() ocommon.Address {
+	return ocommon.Address(this)
+}
+
+func (this testSigner) Sign(ctx context.Context, tx *types.MutableTransaction) error {
+	if tx.Payer == ocommon.ADDRESS_EMPTY {
+		tx.Payer = this.Address()
+	}
+	tx.Sigs = append(tx.Sigs, types.Sig{M: 1, SigData: [][]byte{this[:]}})
+	return nil
+}
+
+//testAccounts return distinct header and proof accounts for chainID
+func testAccounts(chainID uint64) *ChainAccounts {
+	return &ChainAccounts{
+		Header: testSigner{1, byte(chainID)},
+		Proof:  testSigner{2, byte(chainID)},
+	}
+}
+
+func relayTestBlock(syncService *SyncService, fromChainID, toChainID uint64, height uint32) error {
+	return syncService.relayBlock(context.Background(), syncService.GetRoute(fromChainID, toChainID), height)
+}
+
+func TestRelayMainBlock(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+
+	txs := sideChain.Txs()
+	assert.Equal(t, 2, len(txs))
+	assert.Equal(t, header_sync.SYNC_BLOCK_HEADER, txs[0].Method)
+	accounts := testAccounts(testSideChainID)
+	assert.Equal(t, accounts.Header.Address(), txs[0].Signer)
+	assert.Equal(t, accounts.Proof.Address(), txs[1].Signer)
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	proofs := sideChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+	assert.Equal(t, testMainChainID, proofs[0].FromChainID)
+	assert.Equal(t, height+1, proofs[0].Height)
+	assert.Equal(t, accounts.Proof.Address(), proofs[0].Address)
+	assert.Equal(t, 0, len(mainChain.Txs()))
+
+	checkpointed, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, height, checkpointed)
+}
+
+func TestRelaySideBlock(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := sideChain.AddBlock(false, [2]uint64{testMainChainID, 5})
+	sideChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testSideChainID, testMainChainID, height))
+
+	assert.Equal(t, []uint32{height + 1}, mainChain.SyncedHeaders())
+	proofs := mainChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+	assert.Equal(t, testSideChainID, proofs[0].FromChainID)
+	assert.Equal(t, height+1, proofs[0].Height)
+	assert.Equal(t, 0, len(sideChain.Txs()))
+}
+
+func TestRelayKeyHeader(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, 0))
+	height := mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	keyHeight := mainChain.AddBlock(true)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, keyHeight))
+
+	assert.Equal(t, []uint32{0, keyHeight}, sideChain.SyncedHeaders())
+	assert.Equal(t, 0, len(sideChain.Proofs()))
+}
+
+func TestRelayMultipleRequestsInOneBlock(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2}, [2]uint64{testSideChainID, 3})
+	mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+
+	//the header is synced once, the next requests find it in HEADER_INDEX
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	proofs := sideChain.Proofs()
+	assert.Equal(t, 3, len(proofs))
+	for _, proof := range proofs {
+		assert.Equal(t, height+1, proof.Height)
+	}
+	assert.NotEqual(t, proofs[0].Proof, proofs[1].Proof)
+	assert.NotEqual(t, proofs[1].Proof, proofs[2].Proof)
+}
+
+func TestRelaySkipsRelayedRequests(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	assert.Nil(t, syncService.store.MarkRelayed(testMainChainID, testSideChainID, 1))
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+
+	proofs := sideChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+}
+
+func TestRelaySkipsProcessedRequests(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	sideChain.SetProcessed(testMainChainID, 1)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	proofs := sideChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+
+	//a proof sent again after a restart without checkpoint is not submitted
+	assert.Nil(t, syncService.sendProof(context.Background(), syncService.GetRoute(testMainChainID, testSideChainID), 2, height))
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayRequests(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	assert.Nil(t, syncService.store.MarkRelayed(testMainChainID, testSideChainID, 2))
+	relayed, err := syncService.RelayRequests(context.Background(), route, height, []uint64{2})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{2}, relayed)
+	proofs := sideChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+	assert.Equal(t, height+1, proofs[0].Height)
+	relayedInStore, err := syncService.store.IsRelayed(testMainChainID, testSideChainID, 1)
+	assert.Nil(t, err)
+	assert.False(t, relayedInStore)
+
+	relayed, err = syncService.RelayRequests(context.Background(), route, height, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2}, relayed)
+	assert.Equal(t, 2, len(sideChain.Proofs()))
+}
+
+func TestSyncHeaders(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	for height := uint32(1); height <= 25; height++ {
+		mainChain.AddBlock(height == 5 || height == 22)
+	}
+	assert.Nil(t, syncService.syncHeader(context.Background(), route, 3))
+
+	result, err := syncService.SyncHeaders(context.Background(), route, 0, 25, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{0, 5, 22}, result.Synced)
+	assert.Equal(t, []uint32{}, result.Present)
+
+	result, err = syncService.SyncHeaders(context.Background(), route, 0, 24, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{0, 3, 5, 22}, result.Present)
+	assert.Equal(t, 21, len(result.Synced))
+	assert.Equal(t, uint32(1), result.Synced[0])
+	assert.Equal(t, uint32(24), result.Synced[20])
+	//one tx for header 3, one for the key headers, two for the others
+	assert.Equal(t, 4, len(sideChain.Txs()))
+	assert.Equal(t, 25, len(sideChain.SyncedHeaders()))
+
+	mainChain.FailNext("GetBlockByHeight", 100)
+	result, err = syncService.SyncHeaders(context.Background(), route, 24, 25, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, []uint32{24}, result.Present)
+}
+
+func TestIsRequestProcessed(t *testing.T) {
+	syncService, mainChain, _, cleanup := newTestService(t)
+	defer cleanup()
+
+	done, err := syncService.IsRequestProcessed(context.Background(), mainChain, testSideChainID, 3)
+	assert.Nil(t, err)
+	assert.False(t, done)
+	mainChain.SetProcessed(testSideChainID, 3)
+	done, err = syncService.IsRequestProcessed(context.Background(), mainChain, testSideChainID, 3)
+	assert.Nil(t, err)
+	assert.True(t, done)
+	done, err = syncService.IsRequestProcessed(context.Background(), mainChain, testMainChainID, 3)
+	assert.Nil(t, err)
+	assert.False(t, done)
+}
+
+func TestRelayMalformedNotify(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddEvent(height, &sdkcom.SmartContactEvent{
+		Notify: []*sdkcom.NotifyEventInfo{
+			{ContractAddress: utils.CrossChainContractAddress.ToHexString(), States: []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX}},
+			{ContractAddress: utils.CrossChainContractAddress.ToHexString(), States: "00"},
+			{ContractAddress: utils.OngContractAddress.ToHexString(), States: []interface{}{"transfer"}},
+		},
+	})
+	mainChain.AddBlock(false)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayRetry(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	mainChain.FailNext("GetBlockByHeight", 2)
+	mainChain.FailNext("GetSmartContractEventByBlock", 2)
+	mainChain.FailNext("GetCrossStatesProof", 2)
+	sideChain.FailNext("GetStorage", 2)
+	sideChain.FailNext("SendTransaction", 2)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayTxFailure(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	sideChain.FailTx(header_sync.SYNC_BLOCK_HEADER, "header verify failed")
+	sideChain.FailTx(cross_chain.PROCESS_CROSS_CHAIN_TX, "proof verify failed")
+	sideChain.DropTx(cross_chain.PROCESS_CROSS_CHAIN_TX, 1)
+	sideChain.FailNext("GetSmartContractEvent", 1)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayTxLost(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	sideChain.LoseTx(header_sync.SYNC_BLOCK_HEADER, 1)
+	sideChain.LoseTx(cross_chain.PROCESS_CROSS_CHAIN_TX, 1)
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+	//not sent again once found executed
+	assert.Equal(t, 2, len(syncService.GetRoute(testMainChainID, testSideChainID).Status().LastTxs))
+}
+
+func TestRelayTxFailureExhausted(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	for i := 0; i < 3; i++ {
+		sideChain.FailTx(cross_chain.PROCESS_CROSS_CHAIN_TX, "proof verify failed")
+	}
+	assert.NotNil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 0, len(sideChain.Proofs()))
+	_, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayEventsFailure(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	mainChain.FailNext("GetSmartContractEventByBlock", 3)
+	assert.NotNil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 0, len(sideChain.Txs()))
+	_, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+}
+
+func TestRelayProofFailure(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	mainChain.FailNext("GetCrossStatesProof", 3)
+	assert.NotNil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+
+	//the second request is still relayed but the height is not checkpointed
+	assert.Equal(t, []uint32{height + 1}, sideChain.SyncedHeaders())
+	assert.Equal(t, 1, len(sideChain.Proofs()))
+	relayed, err := syncService.store.IsRelayed(testMainChainID, testSideChainID, 1)
+	assert.Nil(t, err)
+	assert.False(t, relayed)
+	_, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	//re-processing the block only relays the failed request
+	assert.Nil(t, relayTestBlock(syncService, testMainChainID, testSideChainID, height))
+	assert.Equal(t, 2, len(sideChain.Proofs()))
+	checkpointed, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, height, checkpointed)
+}
+
+func TestGetStartHeight(t *testing.T) {
+	syncService, _, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	sideChain.SetSyncHeight(testMainChainID, 10)
+	height, err := syncService.getStartHeight(context.Background(), route)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	assert.Nil(t, syncService.store.PutHeight(testMainChainID, testSideChainID, 20))
+	height, err = syncService.getStartHeight(context.Background(), route)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(21), height)
+}
+
+func TestRunStop(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	sideChain.AddBlock(false, [2]uint64{testMainChainID, 2})
+	sideChain.AddBlock(false)
+	syncService.Run(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sideChain.Proofs()) == 0 || len(mainChain.Proofs()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests not relayed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	syncService.Stop()
+
+	height, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), height)
+	height, ok, err = syncService.store.GetHeight(testSideChainID, testMainChainID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), height)
+}
+
+func TestStopInFlightBlock(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	height := mainChain.AddBlock(false, [2]uint64{testSideChainID, 1}, [2]uint64{testSideChainID, 2})
+	mainChain.AddBlock(false)
+	//the first proof is confirmed after the shutdown started, then sent again
+	sideChain.DropTx(cross_chain.PROCESS_CROSS_CHAIN_TX, 1)
+	syncService.Run(context.Background())
+
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(route.Status().LastTxs) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("proof not sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	syncService.Stop()
+
+	assert.Equal(t, 2, len(sideChain.Proofs()))
+	checkpointed, ok, err := syncService.store.GetHeight(testMainChainID, testSideChainID)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, height, checkpointed)
+}
+
+func TestRunStartHeightFailure(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false)
+	//the header sync height of both routes is read from a chain failing for a while
+	sideChain.FailNext("GetStorage", 9)
+	mainChain.FailNext("GetStorage", 9)
+	syncService.Run(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sideChain.Proofs()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests not relayed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	syncService.Stop()
+
+	//a shutdown while the chain is down
+	mainChain.FailNext("GetStorage", 1000)
+	syncService, cleanup = newTestServiceWithChains(t, mainChain, sideChain)
+	defer cleanup()
+	syncService.Run(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	assert.NotEqual(t, "", syncService.GetRoute(testSideChainID, testMainChainID).Status().LastError)
+	syncService.Stop()
+}
+
+func TestRunMultipleSideChains(t *testing.T) {
+	mainChain := newSimChain(testMainChainID)
+	sideChain1 := newSimChain(1)
+	sideChain2 := newSimChain(2)
+	linkSimChains(mainChain, sideChain1)
+	linkSimChains(mainChain, sideChain2)
+	syncService, cleanup := newTestServiceWithChains(t, mainChain, sideChain1, sideChain2)
+	defer cleanup()
+	assert.Equal(t, 4, len(syncService.routes))
+
+	mainChain.AddBlock(false, [2]uint64{1, 10}, [2]uint64{2, 20}, [2]uint64{2, 21})
+	mainChain.AddBlock(false)
+	sideChain1.AddBlock(false, [2]uint64{testMainChainID, 30})
+	sideChain1.AddBlock(false)
+	sideChain2.AddBlock(false)
+	syncService.Run(context.Background())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sideChain1.Proofs()) < 1 || len(sideChain2.Proofs()) < 2 || len(mainChain.Proofs()) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("requests not relayed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	syncService.Stop()
+
+	assert.Equal(t, 1, len(sideChain1.Proofs()))
+	assert.Equal(t, 2, len(sideChain2.Proofs()))
+	proofs := mainChain.Proofs()
+	assert.Equal(t, 1, len(proofs))
+	assert.Equal(t, uint64(1), proofs[0].FromChainID)
+	for _, chain := range []*simChain{sideChain1, sideChain2} {
+		for _, tx := range chain.Txs() {
+			assert.Equal(t, chain.chainID, tx.ChainID)
+		}
+	}
+}
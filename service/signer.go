@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+//Signer sign the transactions submitted by the relayer, it may hold the key locally or ask a
+//remote signer
+type Signer interface {
+	//Address of the account paying the gas
+	Address() ocommon.Address
+	//Sign set the payer of tx to Address if not set and add the signature of the account, giving up
+	//once ctx is done
+	Sign(ctx context.Context, tx *types.MutableTransaction) error
+}
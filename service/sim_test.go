@@ -0,0 +1,467 @@
+package service
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontio/crossChainClient/common"
+	sdkcom "github.com/ontio/ontology-go-sdk/common"
+	ocommon "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//simTx is a native invocation built and sent to a simChain
+type simTx struct {
+	//payer of the signed tx
+	Signer   ocommon.Address
+	ChainID  uint64
+	GasPrice uint64
+	GasLimit uint64
+	Contract ocommon.Address
+	Method   string
+	Param    interface{}
+}
+
+//simChain is an in-process Ontology-like chain implementing ChainClient. Blocks carry a VBFT
+//consensus payload and CREATE_CROSS_CHAIN_TX notifies, the header_sync contract storage is updated
+//by SYNC_BLOCK_HEADER invocations, and proofs exist for every request created on the chain
+type simChain struct {
+	lock    sync.Mutex
+	chainID uint64
+	//chains whose headers are synced to this one
+	peers    []*simChain
+	blocks   []*types.Block
+	events   map[uint32][]*sdkcom.SmartContactEvent
+	storage  map[string][]byte
+	requests map[string]uint32
+	txs      []*simTx
+	failures map[string]int
+	//events of the included transactions by hash
+	txEvents map[string]*sdkcom.SmartContactEvent
+	//built transactions not sent yet by nonce
+	pending map[uint32]*simTx
+	nonce   uint32
+	//reasons of the next failing executions and count of the next dropped transactions by method
+	txFailures map[string][]string
+	drops      map[string]int
+	losses     map[string]int
+	//processed requests are marked under another key than doneTxKey
+	otherLayout bool
+	balances    map[ocommon.Address]uint64
+}
+
+//newSimChainPair create a main chain and a side chain that sync headers from each other,
+//both starting with a key block at height 0 whose header sync starts at height 0
+func newSimChainPair(mainChainID, sideChainID uint64) (*simChain, *simChain) {
+	mainChain := newSimChain(mainChainID)
+	sideChain := newSimChain(sideChainID)
+	linkSimChains(mainChain, sideChain)
+	return mainChain, sideChain
+}
+
+//linkSimChains make a and b sync headers from each other starting at height 0
+func linkSimChains(a, b *simChain) {
+	a.peers = append(a.peers, b)
+	b.peers = append(b.peers, a)
+	a.SetSyncHeight(b.chainID, 0)
+	b.SetSyncHeight(a.chainID, 0)
+}
+
+func newSimChain(chainID uint64) *simChain {
+	chain := &simChain{
+		chainID:    chainID,
+		events:     make(map[uint32][]*sdkcom.SmartContactEvent),
+		storage:    make(map[string][]byte),
+		requests:   make(map[string]uint32),
+		failures:   make(map[string]int),
+		txEvents:   make(map[string]*sdkcom.SmartContactEvent),
+		pending:    make(map[uint32]*simTx),
+		txFailures: make(map[string][]string),
+		drops:      make(map[string]int),
+		losses:     make(map[string]int),
+		balances:   make(map[ocommon.Address]uint64),
+	}
+	chain.AddBlock(true)
+	return chain
+}
+
+//AddBlock append a block, which is a key block if keyHeader is set, creating one cross chain
+//request to toChainID per entry of requests ({toChainID, requestID} pairs), and return its height
+func (this *simChain) AddBlock(keyHeader bool, requests ...[2]uint64) uint32 {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	height := uint32(len(this.blocks))
+	blkInfo := &vconfig.VbftBlockInfo{}
+	if keyHeader {
+		blkInfo.NewChainConfig = &vconfig.ChainConfig{}
+	}
+	payload, err := json.Marshal(blkInfo)
+	if err != nil {
+		panic(err)
+	}
+	this.blocks = append(this.blocks, &types.Block{
+		Header: &types.Header{
+			Height:           height,
+			Timestamp:        uint32(time.Now().Unix()),
+			ConsensusData:    this.chainID,
+			ConsensusPayload: payload,
+		},
+	})
+	for i, request := range requests {
+		toChainID, requestID := request[0], request[1]
+		toChainIDBytes, _ := utils.GetUint64Bytes(toChainID)
+		requestIDBytes, _ := utils.GetUint64Bytes(requestID)
+		key := utils.ConcatKey(utils.CrossChainContractAddress, []byte(cross_chain.REQUEST), toChainIDBytes, requestIDBytes)
+		this.requests[string(key)] = height
+		this.events[height] = append(this.events[height], &sdkcom.SmartContactEvent{
+			TxHash: fmt.Sprintf("%d-%d", height, i),
+			State:  1,
+			Notify: []*sdkcom.NotifyEventInfo{{
+				ContractAddress: utils.CrossChainContractAddress.ToHexString(),
+				States:          []interface{}{cross_chain.CREATE_CROSS_CHAIN_TX, float64(toChainID), float64(requestID)},
+			}},
+		})
+	}
+	return height
+}
+
+//AddEvent append a raw event to the block at height
+func (this *simChain) AddEvent(height uint32, event *sdkcom.SmartContactEvent) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.events[height] = append(this.events[height], event)
+}
+
+//FailNext make the next n calls of method return an error
+func (this *simChain) FailNext(method string, n int) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.failures[method] += n
+}
+
+//FailTx make the next transaction invoking method fail with reason as notify
+func (this *simChain) FailTx(method string, reason string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.txFailures[method] = append(this.txFailures[method], reason)
+}
+
+//DropTx make the next n transactions invoking method never be included
+func (this *simChain) DropTx(method string, n int) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.drops[method] += n
+}
+
+//LoseTx make the next n transactions invoking method be executed without their event ever being found
+func (this *simChain) LoseTx(method string, n int) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.losses[method] += n
+}
+
+//Txs return the successful transactions submitted so far
+func (this *simChain) Txs() []*simTx {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]*simTx{}, this.txs...)
+}
+
+//SyncedHeaders return the heights of peer headers synced by SYNC_BLOCK_HEADER transactions so far
+func (this *simChain) SyncedHeaders() []uint32 {
+	heights := make([]uint32, 0)
+	for _, tx := range this.Txs() {
+		if tx.Method != header_sync.SYNC_BLOCK_HEADER {
+			continue
+		}
+		for _, raw := range tx.Param.(*header_sync.SyncBlockHeaderParam).Headers {
+			header, err := types.HeaderFromRawBytes(raw)
+			if err != nil {
+				panic(err)
+			}
+			heights = append(heights, header.Height)
+		}
+	}
+	return heights
+}
+
+//Proofs return the params of PROCESS_CROSS_CHAIN_TX transactions submitted so far
+func (this *simChain) Proofs() []*cross_chain.ProcessCrossChainTxParam {
+	params := make([]*cross_chain.ProcessCrossChainTxParam, 0)
+	for _, tx := range this.Txs() {
+		if tx.Method == cross_chain.PROCESS_CROSS_CHAIN_TX {
+			params = append(params, tx.Param.(*cross_chain.ProcessCrossChainTxParam))
+		}
+	}
+	return params
+}
+
+//SetSyncHeight set the header_sync current height recorded for chainID
+func (this *simChain) SetSyncHeight(chainID uint64, height uint32) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	chainIDBytes, _ := utils.GetUint64Bytes(chainID)
+	heightBytes, _ := utils.GetUint32Bytes(height)
+	this.putStorage(utils.HeaderSyncContractAddress, common.ConcatKey([]byte(header_sync.CURRENT_HEIGHT), chainIDBytes), heightBytes)
+}
+
+func (this *simChain) fail(method string) error {
+	if this.failures[method] > 0 {
+		this.failures[method]--
+		return fmt.Errorf("simulated %s failure", method)
+	}
+	return nil
+}
+
+func (this *simChain) putStorage(contract ocommon.Address, key, value []byte) {
+	this.storage[contract.ToHexString()+hex.EncodeToString(key)] = value
+}
+
+func (this *simChain) GetCurrentBlockHeight() (uint32, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetCurrentBlockHeight"); err != nil {
+		return 0, err
+	}
+	return uint32(len(this.blocks) - 1), nil
+}
+
+func (this *simChain) GetBlockByHeight(height uint32) (*types.Block, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetBlockByHeight"); err != nil {
+		return nil, err
+	}
+	if int(height) >= len(this.blocks) {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	return this.blocks[height], nil
+}
+
+func (this *simChain) GetSmartContractEventByBlock(height uint32) ([]*sdkcom.SmartContactEvent, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetSmartContractEventByBlock"); err != nil {
+		return nil, err
+	}
+	if int(height) >= len(this.blocks) {
+		return nil, fmt.Errorf("block %d not found", height)
+	}
+	return this.events[height], nil
+}
+
+func (this *simChain) GetStorage(contractAddress string, key []byte) ([]byte, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetStorage"); err != nil {
+		return nil, err
+	}
+	return this.storage[contractAddress+hex.EncodeToString(key)], nil
+}
+
+func (this *simChain) GetCrossStatesProof(height uint32, key []byte) (*sdkcom.CrossStatesProof, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetCrossStatesProof"); err != nil {
+		return nil, err
+	}
+	created, ok := this.requests[string(key)]
+	if !ok || created > height {
+		return nil, fmt.Errorf("no cross states for key %x at height %d", key, height)
+	}
+	return &sdkcom.CrossStatesProof{
+		Type:      "MerkleProof",
+		AuditPath: fmt.Sprintf("%d:%x", height, key),
+	}, nil
+}
+
+func (this *simChain) NewNativeInvokeTransaction(chainID, gasPrice, gasLimit uint64, version byte, contractAddress ocommon.Address,
+	method string, params []interface{}) (*types.MutableTransaction, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.nonce++
+	this.pending[this.nonce] = &simTx{
+		ChainID:  chainID,
+		GasPrice: gasPrice,
+		GasLimit: gasLimit,
+		Contract: contractAddress,
+		Method:   method,
+		Param:    params[0],
+	}
+	return &types.MutableTransaction{
+		GasPrice: gasPrice,
+		GasLimit: gasLimit,
+		TxType:   types.Invoke,
+		Nonce:    this.nonce,
+	}, nil
+}
+
+func (this *simChain) SendTransaction(mutTx *types.MutableTransaction) (ocommon.Uint256, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("SendTransaction"); err != nil {
+		return ocommon.UINT256_EMPTY, err
+	}
+	tx, ok := this.pending[mutTx.Nonce]
+	if !ok {
+		return ocommon.UINT256_EMPTY, fmt.Errorf("unknown tx %d", mutTx.Nonce)
+	}
+	if len(mutTx.Sigs) == 0 || mutTx.Payer == ocommon.ADDRESS_EMPTY {
+		return ocommon.UINT256_EMPTY, fmt.Errorf("tx %d not signed", mutTx.Nonce)
+	}
+	delete(this.pending, mutTx.Nonce)
+	tx.Signer = mutTx.Payer
+	var txHash ocommon.Uint256
+	binary.LittleEndian.PutUint32(txHash[:], mutTx.Nonce)
+	if this.drops[tx.Method] > 0 {
+		this.drops[tx.Method]--
+		return txHash, nil
+	}
+	if reasons := this.txFailures[tx.Method]; len(reasons) > 0 {
+		this.txFailures[tx.Method] = reasons[1:]
+		this.txEvents[txHash.ToHexString()] = &sdkcom.SmartContactEvent{
+			TxHash: txHash.ToHexString(),
+			State:  0,
+			Notify: []*sdkcom.NotifyEventInfo{{ContractAddress: tx.Contract.ToHexString(), States: reasons[0]}},
+		}
+		return txHash, nil
+	}
+	if param, ok := tx.Param.(*header_sync.SyncBlockHeaderParam); ok && tx.Method == header_sync.SYNC_BLOCK_HEADER {
+		if err := this.syncHeaders(param); err != nil {
+			return ocommon.UINT256_EMPTY, err
+		}
+	}
+	if param, ok := tx.Param.(*cross_chain.ProcessCrossChainTxParam); ok && tx.Method == cross_chain.PROCESS_CROSS_CHAIN_TX {
+		if !this.process(param) {
+			this.txEvents[txHash.ToHexString()] = &sdkcom.SmartContactEvent{
+				TxHash: txHash.ToHexString(),
+				State:  0,
+				Notify: []*sdkcom.NotifyEventInfo{{ContractAddress: tx.Contract.ToHexString(), States: "tx already done"}},
+			}
+			return txHash, nil
+		}
+	}
+	this.txs = append(this.txs, tx)
+	if this.losses[tx.Method] > 0 {
+		this.losses[tx.Method]--
+		return txHash, nil
+	}
+	this.txEvents[txHash.ToHexString()] = &sdkcom.SmartContactEvent{TxHash: txHash.ToHexString(), State: 1}
+	return txHash, nil
+}
+
+func (this *simChain) GetOngBalance(address ocommon.Address) (uint64, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetOngBalance"); err != nil {
+		return 0, err
+	}
+	return this.balances[address], nil
+}
+
+//SetOngBalance set the ONG balance of address
+func (this *simChain) SetOngBalance(address ocommon.Address, balance uint64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.balances[address] = balance
+}
+
+//process mark the request proved by param as done in the cross_chain contract storage, it
+//return false if the request was already processed
+func (this *simChain) process(param *cross_chain.ProcessCrossChainTxParam) bool {
+	//the sim proof is "height:key" with key ending with the request id
+	parts := strings.Split(param.Proof, ":")
+	key, err := hex.DecodeString(parts[len(parts)-1])
+	if err != nil || len(key) < 8 {
+		panic(fmt.Sprintf("invalid sim proof %s", param.Proof))
+	}
+	requestID := binary.LittleEndian.Uint64(key[len(key)-8:])
+	doneKey := doneTxKey(param.FromChainID, requestID)
+	if this.otherLayout {
+		doneKey = append([]byte("other"), doneKey...)
+	}
+	if _, ok := this.storage[utils.CrossChainContractAddress.ToHexString()+hex.EncodeToString(doneKey)]; ok {
+		return false
+	}
+	this.putStorage(utils.CrossChainContractAddress, doneKey, []byte{1})
+	return true
+}
+
+//UseOtherLayout make the cross_chain contract mark the processed requests under another key
+func (this *simChain) UseOtherLayout() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.otherLayout = true
+}
+
+//SetProcessed mark the request requestID from fromChainID as processed
+func (this *simChain) SetProcessed(fromChainID, requestID uint64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.putStorage(utils.CrossChainContractAddress, doneTxKey(fromChainID, requestID), []byte{1})
+}
+
+//doneTxKey is the key of the cross_chain contract storage marking a request processed, spelled out
+//from the layout IsRequestProcessed expects rather than with the helpers under test: "doneTx", then
+//the source chain id and the request id as little endian uint64. UseOtherLayout covers a contract
+//which differs
+func doneTxKey(fromChainID, requestID uint64) []byte {
+	key := make([]byte, 6+8+8)
+	copy(key, "doneTx")
+	binary.LittleEndian.PutUint64(key[6:], fromChainID)
+	binary.LittleEndian.PutUint64(key[14:], requestID)
+	return key
+}
+
+//findPeer return the peer which produced header, identified by its ConsensusData
+func (this *simChain) findPeer(header *types.Header) *simChain {
+	for _, peer := range this.peers {
+		if peer.chainID == header.ConsensusData {
+			return peer
+		}
+	}
+	return nil
+}
+
+//syncHeaders apply a SYNC_BLOCK_HEADER invocation to the header_sync contract storage
+func (this *simChain) syncHeaders(param *header_sync.SyncBlockHeaderParam) error {
+	for _, raw := range param.Headers {
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			return fmt.Errorf("types.HeaderFromRawBytes error:%s", err)
+		}
+		peer := this.findPeer(header)
+		if peer == nil {
+			return fmt.Errorf("header %d of unknown chain", header.Height)
+		}
+		chainIDBytes, _ := utils.GetUint64Bytes(peer.chainID)
+		currentKey := common.ConcatKey([]byte(header_sync.CURRENT_HEIGHT), chainIDBytes)
+		heightBytes, _ := utils.GetUint32Bytes(header.Height)
+		hash := header.Hash()
+		this.putStorage(utils.HeaderSyncContractAddress,
+			common.ConcatKey([]byte(header_sync.HEADER_INDEX), chainIDBytes, heightBytes), hash.ToArray())
+		current, err := utils.GetBytesUint32(this.storage[utils.HeaderSyncContractAddress.ToHexString()+hex.EncodeToString(currentKey)])
+		if err != nil || header.Height > current {
+			this.putStorage(utils.HeaderSyncContractAddress, currentKey, heightBytes)
+		}
+	}
+	return nil
+}
+
+func (this *simChain) GetSmartContractEvent(txHash string) (*sdkcom.SmartContactEvent, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if err := this.fail("GetSmartContractEvent"); err != nil {
+		return nil, err
+	}
+	return this.txEvents[txHash], nil
+}
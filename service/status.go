@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	ocommon "github.com/ontio/ontology/common"
+)
+
+//AccountBalance is the ONG balance of a relayer account paying the gas on a chain
+type AccountBalance struct {
+	//header, proof, or header+proof if both use the account
+	Role    string
+	Address string
+	//in the smallest unit, ONG_DECIMALS of them make one ONG
+	Balance uint64
+}
+
+//SyncStatus is the cross chain sync state of a route read from both chains
+type SyncStatus struct {
+	Name        string
+	FromChainID uint64
+	ToChainID   uint64
+	//current height of route.From
+	SourceHeight uint32
+	//header_sync CURRENT_HEIGHT of route.From on route.To
+	SyncHeight uint32
+	//SourceHeight - SyncHeight
+	Lag uint32
+	//accounts of route.To
+	Balances []*AccountBalance
+	//blocks of route.From scanned for PendingRequests, from ScanStart to SourceHeight
+	ScanStart uint32
+	//requests for route.To created in the scanned blocks and not processed by route.To yet
+	PendingRequests []uint64
+	//what could not be read, the matching fields are zero
+	Errors []string `json:",omitempty"`
+}
+
+//SyncStatus read the state of route from its chains, scanning the last blocks blocks of route.From for
+//pending requests. It does not use the checkpoint store, so it works while another relayer runs
+func (this *SyncService) SyncStatus(ctx context.Context, route *Route, blocks uint32) *SyncStatus {
+	status := &SyncStatus{
+		Name:            route.Name,
+		FromChainID:     route.From.ChainID,
+		ToChainID:       route.To.ChainID,
+		Balances:        make([]*AccountBalance, 0),
+		PendingRequests: make([]uint64, 0),
+	}
+	addError := func(format string, args ...interface{}) {
+		status.Errors = append(status.Errors, fmt.Sprintf(format, args...))
+	}
+	sourceErr := this.retry(ctx, "GetCurrentBlockHeight", func() (err error) {
+		status.SourceHeight, err = route.From.Client.GetCurrentBlockHeight()
+		return
+	})
+	if sourceErr != nil {
+		addError("chain %d GetCurrentBlockHeight error:%s", route.From.ChainID, sourceErr)
+	}
+	var err error
+	status.SyncHeight, err = this.GetSyncHeight(ctx, route.To, route.From.ChainID)
+	if err != nil {
+		addError("chain %d GetSyncHeight error:%s", route.To.ChainID, err)
+	} else if status.SourceHeight > status.SyncHeight {
+		status.Lag = status.SourceHeight - status.SyncHeight
+	}
+
+	header, proof := route.To.Accounts.Header.Address(), route.To.Accounts.Proof.Address()
+	roles, addresses := []string{"header", "proof"}, []ocommon.Address{header, proof}
+	if header == proof {
+		roles, addresses = []string{"header+proof"}, addresses[:1]
+	}
+	for i, address := range addresses {
+		account := &AccountBalance{Role: roles[i], Address: address.ToBase58()}
+		err = this.retry(ctx, "GetOngBalance", func() (err error) {
+			account.Balance, err = route.To.Client.GetOngBalance(address)
+			return
+		})
+		if err != nil {
+			addError("chain %d GetOngBalance of %s error:%s", route.To.ChainID, account.Address, err)
+			continue
+		}
+		status.Balances = append(status.Balances, account)
+	}
+
+	if blocks == 0 || sourceErr != nil {
+		return status
+	}
+	if status.SourceHeight >= blocks {
+		status.ScanStart = status.SourceHeight - blocks + 1
+	}
+	//uint64 so that SourceHeight may be math.MaxUint32
+	for h := uint64(status.ScanStart); h <= uint64(status.SourceHeight); h++ {
+		height := uint32(h)
+		requestIDs, err := this.findRequests(ctx, route, height)
+		if err != nil {
+			addError("chain %d block %d: %s", route.From.ChainID, height, err)
+			continue
+		}
+		for _, requestID := range requestIDs {
+			done, err := this.IsRequestProcessed(ctx, route.To.Client, route.From.ChainID, requestID)
+			if err != nil {
+				addError("chain %d request %d IsRequestProcessed error:%s", route.To.ChainID, requestID, err)
+				continue
+			}
+			if !done {
+				status.PendingRequests = append(status.PendingRequests, requestID)
+			}
+		}
+	}
+	return status
+}
+
+//SyncStatuses return the SyncStatus of every route
+func (this *SyncService) SyncStatuses(ctx context.Context, blocks uint32) []*SyncStatus {
+	statuses := make([]*SyncStatus, 0, len(this.routes))
+	for _, route := range this.routes {
+		statuses = append(statuses, this.SyncStatus(ctx, route, blocks))
+	}
+	return statuses
+}
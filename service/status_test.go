@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncStatus(t *testing.T) {
+	syncService, mainChain, sideChain, cleanup := newTestService(t)
+	defer cleanup()
+	route := syncService.GetRoute(testMainChainID, testSideChainID)
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 1})
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 2}, [2]uint64{testMainChainID, 7})
+	mainChain.AddBlock(false)
+	mainChain.AddBlock(false, [2]uint64{testSideChainID, 3})
+	sideChain.SetProcessed(testMainChainID, 2)
+	sideChain.SetSyncHeight(testMainChainID, 1)
+	accounts := testAccounts(testSideChainID)
+	sideChain.SetOngBalance(accounts.Header.Address(), 5)
+
+	status := syncService.SyncStatus(context.Background(), route, 3)
+	assert.Equal(t, uint32(4), status.SourceHeight)
+	assert.Equal(t, uint32(1), status.SyncHeight)
+	assert.Equal(t, uint32(3), status.Lag)
+	assert.Equal(t, 2, len(status.Balances))
+	assert.Equal(t, "header", status.Balances[0].Role)
+	assert.Equal(t, uint64(5), status.Balances[0].Balance)
+	assert.Equal(t, uint64(0), status.Balances[1].Balance)
+	assert.Equal(t, uint32(2), status.ScanStart)
+	assert.Equal(t, []uint64{3}, status.PendingRequests)
+	assert.Nil(t, status.Errors)
+
+	sideChain.FailNext("GetOngBalance", 3)
+	status = syncService.SyncStatus(context.Background(), route, 10)
+	assert.Equal(t, uint32(0), status.ScanStart)
+	assert.Equal(t, []uint64{1, 3}, status.PendingRequests)
+	assert.Equal(t, 1, len(status.Balances))
+	assert.Equal(t, "proof", status.Balances[0].Role)
+	assert.Equal(t, 1, len(status.Errors))
+
+	mainChain.FailNext("GetCurrentBlockHeight", 3)
+	status = syncService.SyncStatus(context.Background(), route, 10)
+	assert.Equal(t, 1, len(status.Errors))
+	assert.Equal(t, []uint64{}, status.PendingRequests)
+	assert.Equal(t, 2, len(syncService.SyncStatuses(context.Background(), 10)))
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+//timeout of the requests to a remote signer
+const REQUEST_TIMEOUT = 10 * time.Second
+
+//RemoteSigner sign transactions through a remote signer holding the key of an account
+type RemoteSigner struct {
+	url     string
+	token   string
+	address common.Address
+	client  *http.Client
+}
+
+//NewRemoteSigner connect to the remote signer at url and check that it serves the account with
+//base58 address
+func NewRemoteSigner(url, token, address string) (*RemoteSigner, error) {
+	addr, err := common.AddressFromBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s error:%s", address, err)
+	}
+	signer := &RemoteSigner{
+		url:     strings.TrimRight(url, "/"),
+		token:   token,
+		address: addr,
+		client:  &http.Client{Timeout: REQUEST_TIMEOUT},
+	}
+	resp := &AccountsResponse{}
+	err = signer.call(context.Background(), http.MethodGet, ACCOUNTS_PATH, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("get accounts of %s error:%s", url, err)
+	}
+	for _, served := range resp.Addresses {
+		if served == address {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("signer %s does not serve account %s", url, address)
+}
+
+func (this *RemoteSigner) Address() common.Address {
+	return this.address
+}
+
+func (this *RemoteSigner) Sign(ctx context.Context, tx *types.MutableTransaction) error {
+	if tx.Payer == common.ADDRESS_EMPTY {
+		tx.Payer = this.address
+	}
+	data, err := encodeTx(tx)
+	if err != nil {
+		return err
+	}
+	resp := &SignResponse{}
+	err = this.call(ctx, http.MethodPost, SIGN_PATH, &SignRequest{Address: this.address.ToBase58(), Tx: data}, resp)
+	if err != nil {
+		return err
+	}
+	signed, err := decodeTx(resp.Tx)
+	if err != nil {
+		return fmt.Errorf("decode signed tx error:%s", err)
+	}
+	if !sameTx(tx, signed) {
+		return fmt.Errorf("signer returned a different tx")
+	}
+	tx.Sigs = signed.Sigs
+	return nil
+}
+
+func (this *RemoteSigner) call(ctx context.Context, method, path string, req, resp interface{}) error {
+	var body bytes.Buffer
+	if req != nil {
+		err := json.NewEncoder(&body).Encode(req)
+		if err != nil {
+			return fmt.Errorf("encode request error:%s", err)
+		}
+	}
+	httpReq, err := http.NewRequest(method, this.url+path, &body)
+	if err != nil {
+		return fmt.Errorf("http.NewRequest error:%s", err)
+	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Header.Set("Authorization", "Bearer "+this.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := this.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s %s error:%s", method, path, err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		errResp := &SignResponse{}
+		json.NewDecoder(httpResp.Body).Decode(errResp)
+		return fmt.Errorf("%s %s status %d:%s", method, path, httpResp.StatusCode, errResp.Error)
+	}
+	err = json.NewDecoder(httpResp.Body).Decode(resp)
+	if err != nil {
+		return fmt.Errorf("decode response error:%s", err)
+	}
+	return nil
+}
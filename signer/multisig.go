@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ontio/crossChainClient/log"
+	"github.com/ontio/ontology-crypto/keypair"
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+//interval between two reads of the signatures in the queue
+const QUEUE_POLL_INTERVAL = time.Second
+
+//MultiSigner sign transactions paid by the M-of-N multi-signature address of pubKeys, with the local
+//accounts among the N operators and, until M signatures are collected, with the signatures the other
+//operators add to a SignQueue
+type MultiSigner struct {
+	m        int
+	pubKeys  []keypair.PublicKey
+	address  common.Address
+	accounts []*sdk.Account
+	queue    *SignQueue
+	timeout  time.Duration
+	interval time.Duration
+}
+
+//NewMultiSigner create a signer of the M-of-N address of pubKeys. queue may be nil if accounts hold at
+//least m keys, timeout is how long to wait for the signatures of the queue
+func NewMultiSigner(m int, pubKeys []keypair.PublicKey, accounts []*sdk.Account, queue *SignQueue,
+	timeout time.Duration) (*MultiSigner, error) {
+	pubKeys = keypair.SortPublicKeys(append([]keypair.PublicKey{}, pubKeys...))
+	address, err := MultiSigAddress(m, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if indexOfKey(pubKeys, account.PublicKey) < 0 {
+			return nil, fmt.Errorf("account %s is not an operator of the multi-signature", account.Address.ToBase58())
+		}
+	}
+	if len(accounts) < m && queue == nil {
+		return nil, fmt.Errorf("%d local accounts for %d signatures and no signing queue", len(accounts), m)
+	}
+	return &MultiSigner{
+		m:        m,
+		pubKeys:  pubKeys,
+		address:  address,
+		accounts: accounts,
+		queue:    queue,
+		timeout:  timeout,
+		interval: QUEUE_POLL_INTERVAL,
+	}, nil
+}
+
+//MultiSigAddress return the M-of-N address of pubKeys
+func MultiSigAddress(m int, pubKeys []keypair.PublicKey) (common.Address, error) {
+	address, err := types.AddressFromMultiPubKeys(keypair.SortPublicKeys(append([]keypair.PublicKey{}, pubKeys...)), m)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("invalid %d of %d multi-signature:%s", m, len(pubKeys), err)
+	}
+	return address, nil
+}
+
+func (this *MultiSigner) Address() common.Address {
+	return this.address
+}
+
+func (this *MultiSigner) Sign(ctx context.Context, tx *types.MutableTransaction) error {
+	if tx.Payer == common.ADDRESS_EMPTY {
+		tx.Payer = this.address
+	}
+	txHash := tx.Hash()
+	sigs := make([][]byte, len(this.pubKeys))
+	count := 0
+	for _, account := range this.accounts {
+		sig, err := account.Sign(txHash.ToArray())
+		if err != nil {
+			return fmt.Errorf("account %s sign error:%s", account.Address.ToBase58(), err)
+		}
+		i := indexOfKey(this.pubKeys, account.PublicKey)
+		if sigs[i] == nil {
+			sigs[i] = sig
+			count++
+		}
+	}
+	if count < this.m {
+		err := this.collect(ctx, tx, sigs, count)
+		if err != nil {
+			return err
+		}
+	}
+	sig := types.Sig{
+		PubKeys: this.pubKeys,
+		M:       uint16(this.m),
+	}
+	for _, data := range sigs {
+		if data != nil && len(sig.SigData) < this.m {
+			sig.SigData = append(sig.SigData, data)
+		}
+	}
+	tx.Sigs = append(tx.Sigs, sig)
+	return nil
+}
+
+//collect submit tx to the queue and wait for the operators to complete the count signatures of sigs
+//up to M, until the timeout or ctx is done. The tx is removed from the queue on return
+func (this *MultiSigner) collect(ctx context.Context, tx *types.MutableTransaction, sigs [][]byte, count int) error {
+	txHash := tx.Hash()
+	err := this.queue.Submit(tx)
+	if err != nil {
+		return fmt.Errorf("queue tx %s error:%s", txHash.ToHexString(), err)
+	}
+	defer func() {
+		if err := this.queue.Remove(txHash); err != nil {
+			log.Warnf("[MultiSigner] remove tx %s from queue error:%s", txHash.ToHexString(), err)
+		}
+	}()
+	log.Infof("[MultiSigner] tx %s waiting for %d more signatures in %s", txHash.ToHexString(),
+		this.m-count, this.queue.dir)
+	deadline := time.Now().Add(this.timeout)
+	for {
+		queued, err := this.queue.Signatures(txHash)
+		if err != nil {
+			return fmt.Errorf("read signatures of tx %s error:%s", txHash.ToHexString(), err)
+		}
+		for i, pubKey := range this.pubKeys {
+			if sigs[i] != nil {
+				continue
+			}
+			sig, ok := queued[hex.EncodeToString(keypair.SerializePublicKey(pubKey))]
+			if !ok {
+				continue
+			}
+			if err := signature.Verify(pubKey, txHash.ToArray(), sig); err != nil {
+				log.Warnf("[MultiSigner] invalid queued signature of tx %s:%s", txHash.ToHexString(), err)
+				continue
+			}
+			sigs[i] = sig
+			count++
+		}
+		if count >= this.m {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("tx %s got %d of %d signatures before timeout", txHash.ToHexString(), count, this.m)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tx %s got %d of %d signatures: %s", txHash.ToHexString(), count, this.m, ctx.Err())
+		case <-time.After(this.interval):
+		}
+	}
+}
+
+func indexOfKey(pubKeys []keypair.PublicKey, pubKey keypair.PublicKey) int {
+	for i, key := range pubKeys {
+		if keypair.ComparePublicKey(key, pubKey) {
+			return i
+		}
+	}
+	return -1
+}
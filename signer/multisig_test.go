@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOperators(t *testing.T, n int) ([]*sdk.Account, []keypair.PublicKey) {
+	accounts := make([]*sdk.Account, n)
+	pubKeys := make([]keypair.PublicKey, n)
+	for i := range accounts {
+		accounts[i] = newTestAccount(t)
+		pubKeys[i] = accounts[i].PublicKey
+	}
+	return accounts, pubKeys
+}
+
+func newTestQueue(t *testing.T) (*SignQueue, func()) {
+	dir, err := ioutil.TempDir("", "signqueue")
+	assert.Nil(t, err)
+	queue, err := NewSignQueue(dir)
+	assert.Nil(t, err)
+	return queue, func() { os.RemoveAll(dir) }
+}
+
+func assertMultiSigned(t *testing.T, tx *types.MutableTransaction, signer *MultiSigner, pubKeys []keypair.PublicKey, m int) {
+	address, err := types.AddressFromMultiPubKeys(pubKeys, m)
+	assert.Nil(t, err)
+	assert.Equal(t, address, signer.Address())
+	assert.Equal(t, address, tx.Payer)
+	assert.Equal(t, 1, len(tx.Sigs))
+	assert.Equal(t, uint16(m), tx.Sigs[0].M)
+	assert.Equal(t, m, len(tx.Sigs[0].SigData))
+	txHash := tx.Hash()
+	assert.Nil(t, signature.VerifyMultiSignature(txHash.ToArray(), tx.Sigs[0].PubKeys, m, tx.Sigs[0].SigData))
+}
+
+func TestNewMultiSigner(t *testing.T) {
+	accounts, pubKeys := newTestOperators(t, 3)
+	_, err := NewMultiSigner(4, pubKeys, accounts, nil, time.Second)
+	assert.NotNil(t, err)
+	_, err = NewMultiSigner(2, pubKeys, []*sdk.Account{newTestAccount(t)}, nil, time.Second)
+	assert.NotNil(t, err)
+	_, err = NewMultiSigner(2, pubKeys, accounts[:1], nil, time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestMultiSignerLocal(t *testing.T) {
+	accounts, pubKeys := newTestOperators(t, 3)
+	signer, err := NewMultiSigner(2, pubKeys, accounts[1:], nil, time.Second)
+	assert.Nil(t, err)
+	tx := newTestTx(t)
+	assert.Nil(t, signer.Sign(context.Background(), tx))
+	assertMultiSigned(t, tx, signer, pubKeys, 2)
+	address, err := MultiSigAddress(2, pubKeys)
+	assert.Nil(t, err)
+	assert.Equal(t, signer.Address(), address)
+}
+
+func TestMultiSignerQueue(t *testing.T) {
+	queue, cleanup := newTestQueue(t)
+	defer cleanup()
+	accounts, pubKeys := newTestOperators(t, 3)
+	signer, err := NewMultiSigner(2, pubKeys, accounts[:1], queue, 5*time.Second)
+	assert.Nil(t, err)
+	signer.interval = 10 * time.Millisecond
+
+	//the other operators sign what shows up in the queue
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			txs, err := queue.Pending()
+			assert.Nil(t, err)
+			if len(txs) != 0 {
+				assert.Nil(t, queue.AddSignature(txs[0], accounts[2]))
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	tx := newTestTx(t)
+	assert.Nil(t, signer.Sign(context.Background(), tx))
+	<-done
+	assertMultiSigned(t, tx, signer, pubKeys, 2)
+
+	txs, err := queue.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+}
+
+func TestMultiSignerQueueTimeout(t *testing.T) {
+	queue, cleanup := newTestQueue(t)
+	defer cleanup()
+	accounts, pubKeys := newTestOperators(t, 3)
+	signer, err := NewMultiSigner(3, pubKeys, accounts[:1], queue, 50*time.Millisecond)
+	assert.Nil(t, err)
+	signer.interval = 10 * time.Millisecond
+
+	tx := newTestTx(t)
+	tx.Payer = signer.Address()
+	//a signature of another tx does not count
+	other := newTestTx(t)
+	other.Nonce++
+	otherHash := other.Hash()
+	sig, err := accounts[1].Sign(otherHash.ToArray())
+	assert.Nil(t, err)
+	txHash := tx.Hash()
+	assert.Nil(t, queue.writeFile(sigFileName(txHash, accounts[1].PublicKey), hex.EncodeToString(sig)))
+
+	err = signer.Sign(context.Background(), tx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 of 3 signatures")
+	txs, err := queue.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+}
+
+func TestMultiSignerQueueCanceled(t *testing.T) {
+	queue, cleanup := newTestQueue(t)
+	defer cleanup()
+	accounts, pubKeys := newTestOperators(t, 3)
+	signer, err := NewMultiSigner(2, pubKeys, accounts[:1], queue, time.Hour)
+	assert.Nil(t, err)
+	signer.interval = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = signer.Sign(ctx, newTestTx(t))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	txs, err := queue.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(txs))
+}
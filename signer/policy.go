@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	httpcom "github.com/ontio/ontology/http/base/common"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/vm/neovm"
+)
+
+//RelayMethod is a native contract method invoked by the relayer
+type RelayMethod struct {
+	Contract common.Address
+	Method   string
+}
+
+//RelayMethods are the only invocations the signers sign
+var RelayMethods = []*RelayMethod{
+	{Contract: utils.HeaderSyncContractAddress, Method: header_sync.SYNC_BLOCK_HEADER},
+	{Contract: utils.CrossChainContractAddress, Method: cross_chain.PROCESS_CROSS_CHAIN_TX},
+}
+
+//CheckRelayTx decode the payload of tx and return the relay method it invokes. The code must be the
+//one built for a native invoke: the parameters, which may only push data and build structs and
+//arrays from it, followed by the invoke of one of the RelayMethods
+func CheckRelayTx(tx *types.MutableTransaction) (*RelayMethod, error) {
+	if tx.TxType != types.Invoke {
+		return nil, fmt.Errorf("tx type %d is not invoke", tx.TxType)
+	}
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("tx payload is not an invoke code")
+	}
+	for _, relay := range RelayMethods {
+		invokeCode, err := httpcom.BuildNativeInvokeCode(relay.Contract, 0, relay.Method, []interface{}{})
+		if err != nil {
+			return nil, fmt.Errorf("BuildNativeInvokeCode error:%s", err)
+		}
+		if !bytes.HasSuffix(invoke.Code, invokeCode) {
+			continue
+		}
+		err = checkParamsCode(invoke.Code[:len(invoke.Code)-len(invokeCode)])
+		if err != nil {
+			return nil, fmt.Errorf("%s parameters: %s", relay.Method, err)
+		}
+		return relay, nil
+	}
+	return nil, fmt.Errorf("tx does not invoke %s nor %s", header_sync.SYNC_BLOCK_HEADER,
+		cross_chain.PROCESS_CROSS_CHAIN_TX)
+}
+
+//checkParamsCode check that code only pushes data and builds structs and arrays from it
+func checkParamsCode(code []byte) error {
+	for i := 0; i < len(code); {
+		op := neovm.OpCode(code[i])
+		start := i
+		i++
+		size := uint64(0)
+		switch {
+		case op >= neovm.PUSHBYTES1 && op <= neovm.PUSHBYTES75:
+			size = uint64(op)
+		case op >= neovm.PUSHDATA1 && op <= neovm.PUSHDATA4:
+			//the size is in the next 1, 2 or 4 bytes
+			n := 1 << uint(op-neovm.PUSHDATA1)
+			if len(code)-i < n {
+				return fmt.Errorf("truncated push at %d", start)
+			}
+			length := make([]byte, 8)
+			copy(length, code[i:i+n])
+			size = binary.LittleEndian.Uint64(length)
+			i += n
+		case op == neovm.PUSH0 || op == neovm.PUSHM1 || (op >= neovm.PUSH1 && op <= neovm.PUSH16):
+		case op == neovm.NEWSTRUCT || op == neovm.PACK || op == neovm.APPEND || op == neovm.TOALTSTACK ||
+			op == neovm.DUPFROMALTSTACK || op == neovm.FROMALTSTACK:
+		default:
+			return fmt.Errorf("opcode 0x%02x at %d is not allowed", byte(op), start)
+		}
+		if size > uint64(len(code)-i) {
+			return fmt.Errorf("truncated push at %d", start)
+		}
+		i += int(size)
+	}
+	return nil
+}
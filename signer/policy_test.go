@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"testing"
+
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/payload"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/service/native/cross_chain"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/ontio/ontology/vm/neovm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRelayTx(t *testing.T) {
+	relay, err := CheckRelayTx(newTestTx(t))
+	assert.Nil(t, err)
+	assert.Equal(t, header_sync.SYNC_BLOCK_HEADER, relay.Method)
+
+	tx, err := sdk.NewOntologySdk().Native.NewNativeInvokeTransaction(1, 0, 20000, 0, utils.CrossChainContractAddress,
+		cross_chain.PROCESS_CROSS_CHAIN_TX, []interface{}{&cross_chain.ProcessCrossChainTxParam{
+			FromChainID: 1,
+			Height:      10,
+			Proof:       string(make([]byte, 300)),
+		}})
+	assert.Nil(t, err)
+	relay, err = CheckRelayTx(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.CrossChainContractAddress, relay.Contract)
+
+	tx, err = sdk.NewOntologySdk().Native.NewNativeInvokeTransaction(1, 0, 20000, 0, utils.OngContractAddress,
+		"transfer", []interface{}{common.ADDRESS_EMPTY})
+	assert.Nil(t, err)
+	_, err = CheckRelayTx(tx)
+	assert.NotNil(t, err)
+
+	//another call hidden in the parameters
+	tx = newTestTx(t)
+	code := tx.Payload.(*payload.InvokeCode).Code
+	tx.Payload = &payload.InvokeCode{Code: append([]byte{byte(neovm.PUSH1), byte(neovm.SYSCALL)}, code...)}
+	_, err = CheckRelayTx(tx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "opcode 0x68 at 1 is not allowed")
+
+	//a push swallowing the start of the invoke
+	tx.Payload = &payload.InvokeCode{Code: append([]byte{byte(neovm.PUSHDATA1), 0xff}, code...)}
+	_, err = CheckRelayTx(tx)
+	assert.NotNil(t, err)
+
+	tx = newTestTx(t)
+	tx.TxType = types.Deploy
+	_, err = CheckRelayTx(tx)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	TX_FILE_EXT  = ".tx"
+	SIG_FILE_EXT = ".sig"
+)
+
+//SignQueue is a directory shared with the co-signing operators of multi-signature transactions.
+//
+//The relayer writes each transaction waiting for signatures to "<tx hash>.tx" as hex, every operator
+//adds its signature of the tx hash to "<tx hash>.<hex public key>.sig" as hex, and the relayer removes
+//the files once enough signatures are collected. Files are written to a temporary name and renamed,
+//so that readers never see a partial file
+type SignQueue struct {
+	dir string
+}
+
+func NewSignQueue(dir string) (*SignQueue, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("create queue dir error:%s", err)
+	}
+	return &SignQueue{dir: dir}, nil
+}
+
+//Submit add tx to the transactions waiting for signatures
+func (this *SignQueue) Submit(tx *types.MutableTransaction) error {
+	data, err := encodeTx(tx)
+	if err != nil {
+		return err
+	}
+	txHash := tx.Hash()
+	return this.writeFile(txHash.ToHexString()+TX_FILE_EXT, data)
+}
+
+//Pending return the transactions waiting for signatures
+func (this *SignQueue) Pending() ([]*types.MutableTransaction, error) {
+	files, err := filepath.Glob(filepath.Join(this.dir, "*"+TX_FILE_EXT))
+	if err != nil {
+		return nil, fmt.Errorf("list queue error:%s", err)
+	}
+	txs := make([]*types.MutableTransaction, 0, len(files))
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				//removed by the relayer meanwhile
+				continue
+			}
+			return nil, fmt.Errorf("read %s error:%s", file, err)
+		}
+		tx, err := decodeTx(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("decode %s error:%s", file, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+//AddSignature sign tx with account and add the signature to the queue
+func (this *SignQueue) AddSignature(tx *types.MutableTransaction, account *sdk.Account) error {
+	txHash := tx.Hash()
+	sig, err := account.Sign(txHash.ToArray())
+	if err != nil {
+		return fmt.Errorf("sign tx error:%s", err)
+	}
+	return this.writeFile(sigFileName(txHash, account.PublicKey), hex.EncodeToString(sig))
+}
+
+//HasSignature return whether the signature of pubKey for the tx txHash is in the queue
+func (this *SignQueue) HasSignature(txHash common.Uint256, pubKey keypair.PublicKey) bool {
+	_, err := os.Stat(filepath.Join(this.dir, sigFileName(txHash, pubKey)))
+	return err == nil
+}
+
+//Signatures return the signatures of the tx txHash in the queue, keyed by hex public key
+func (this *SignQueue) Signatures(txHash common.Uint256) (map[string][]byte, error) {
+	prefix := txHash.ToHexString() + "."
+	files, err := filepath.Glob(filepath.Join(this.dir, prefix+"*"+SIG_FILE_EXT))
+	if err != nil {
+		return nil, fmt.Errorf("list signatures error:%s", err)
+	}
+	sigs := make(map[string][]byte)
+	for _, file := range files {
+		pubKey := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), prefix), SIG_FILE_EXT)
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read %s error:%s", file, err)
+		}
+		sig, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("decode %s error:%s", file, err)
+		}
+		sigs[pubKey] = sig
+	}
+	return sigs, nil
+}
+
+//Remove delete the tx txHash and its signatures from the queue
+func (this *SignQueue) Remove(txHash common.Uint256) error {
+	files, err := filepath.Glob(filepath.Join(this.dir, txHash.ToHexString()+".*"))
+	if err != nil {
+		return fmt.Errorf("list queue error:%s", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s error:%s", file, err)
+		}
+	}
+	return nil
+}
+
+func (this *SignQueue) writeFile(name, data string) error {
+	tmp, err := ioutil.TempFile(this.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("create temp file error:%s", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write %s error:%s", name, err)
+	}
+	err = os.Rename(tmp.Name(), filepath.Join(this.dir, name))
+	if err != nil {
+		return fmt.Errorf("rename %s error:%s", name, err)
+	}
+	return nil
+}
+
+func sigFileName(txHash common.Uint256, pubKey keypair.PublicKey) string {
+	return txHash.ToHexString() + "." + hex.EncodeToString(keypair.SerializePublicKey(pubKey)) + SIG_FILE_EXT
+}
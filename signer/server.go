@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ontio/crossChainClient/log"
+	sdk "github.com/ontio/ontology-go-sdk"
+)
+
+//max size of a sign request body
+const MAX_REQUEST_SIZE = 1 << 20
+
+//Server is the reference remote signer, serving the keys of unlocked wallet accounts to the
+//clients presenting the token of each account. It only signs the relay txs checked by CheckRelayTx
+type Server struct {
+	served map[string]*servedAccount
+}
+
+//ServedAccount is a wallet account served by a Server to the clients presenting Token
+type ServedAccount struct {
+	Account *sdk.Account
+	Token   string
+}
+
+type servedAccount struct {
+	signer *AccountSigner
+	token  string
+}
+
+func NewServer(accounts []*ServedAccount) *Server {
+	server := &Server{served: make(map[string]*servedAccount)}
+	for _, account := range accounts {
+		server.served[account.Account.Address.ToBase58()] = &servedAccount{
+			signer: NewAccountSigner(account.Account),
+			token:  account.Token,
+		}
+	}
+	return server
+}
+
+func (this *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	signers := this.authorized(r)
+	if len(signers) == 0 {
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+		return
+	}
+	switch {
+	case r.URL.Path == ACCOUNTS_PATH && r.Method == http.MethodGet:
+		this.accounts(w, signers)
+	case r.URL.Path == SIGN_PATH && r.Method == http.MethodPost:
+		this.sign(w, r, signers)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+//authorized return the signers of the accounts served to the token of r, by address
+func (this *Server) authorized(r *http.Request) map[string]*AccountSigner {
+	signers := make(map[string]*AccountSigner)
+	header := []byte(r.Header.Get("Authorization"))
+	for address, account := range this.served {
+		expected := []byte("Bearer " + account.token)
+		if account.token != "" && subtle.ConstantTimeCompare(header, expected) == 1 {
+			signers[address] = account.signer
+		}
+	}
+	return signers
+}
+
+func (this *Server) accounts(w http.ResponseWriter, signers map[string]*AccountSigner) {
+	resp := &AccountsResponse{Addresses: make([]string, 0, len(signers))}
+	for address := range signers {
+		resp.Addresses = append(resp.Addresses, address)
+	}
+	writeJson(w, http.StatusOK, resp)
+}
+
+func (this *Server) sign(w http.ResponseWriter, r *http.Request, signers map[string]*AccountSigner) {
+	req := &SignRequest{}
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MAX_REQUEST_SIZE)).Decode(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode request error:%s", err))
+		return
+	}
+	signer, ok := signers[req.Address]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("account %s not found", req.Address))
+		return
+	}
+	tx, err := decodeTx(req.Tx)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode tx error:%s", err))
+		return
+	}
+	if tx.Payer != signer.Address() {
+		writeError(w, http.StatusForbidden, fmt.Errorf("tx payer %s is not %s", tx.Payer.ToBase58(), req.Address))
+		return
+	}
+	relay, err := CheckRelayTx(tx)
+	if err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	err = signer.Sign(r.Context(), tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("sign tx error:%s", err))
+		return
+	}
+	data, err := encodeTx(tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	txHash := tx.Hash()
+	log.Infof("[signer] signed %s tx %s for %s", relay.Method, txHash.ToHexString(), req.Address)
+	writeJson(w, http.StatusOK, &SignResponse{Tx: data})
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, &SignResponse{Error: err.Error()})
+}
+
+func writeJson(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		log.Errorf("[signer] write response error:%s", err)
+	}
+}
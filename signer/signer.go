@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package signer sign relayer transactions with a local wallet account or through a remote signer,
+//so that relay hosts only hold an API token instead of private keys.
+//
+//Remote signer protocol, every request carries the header "Authorization: Bearer <token>":
+//
+//	GET  /v1/accounts  -> {"Addresses":["<base58>",...]}
+//	POST /v1/sign      {"Address":"<base58>","Tx":"<hex>"} -> {"Tx":"<hex>"}
+//
+//Tx is the hex encoded serialization of the transaction. The signer only signs transactions paid by
+//Address and answers the transaction with the signature of Address appended. Errors are answered
+//with a non 200 status and {"Error":"<message>"}
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+const (
+	ACCOUNTS_PATH = "/v1/accounts"
+	SIGN_PATH     = "/v1/sign"
+)
+
+type AccountsResponse struct {
+	Addresses []string
+}
+
+type SignRequest struct {
+	Address string
+	Tx      string
+}
+
+type SignResponse struct {
+	Tx    string `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+//AccountSigner sign transactions with an unlocked wallet account
+type AccountSigner struct {
+	sdk     *sdk.OntologySdk
+	account *sdk.Account
+}
+
+func NewAccountSigner(account *sdk.Account) *AccountSigner {
+	return &AccountSigner{
+		sdk:     sdk.NewOntologySdk(),
+		account: account,
+	}
+}
+
+func (this *AccountSigner) Address() common.Address {
+	return this.account.Address
+}
+
+func (this *AccountSigner) Sign(ctx context.Context, tx *types.MutableTransaction) error {
+	if tx.Payer == common.ADDRESS_EMPTY {
+		tx.Payer = this.account.Address
+	}
+	return this.sdk.SignToTransaction(tx, this.account)
+}
+
+//AddressSigner is an account known by its address only, to read the chains without unlocking the
+//wallet. It cannot sign
+type AddressSigner struct {
+	address common.Address
+}
+
+func NewAddressSigner(address common.Address) *AddressSigner {
+	return &AddressSigner{address: address}
+}
+
+func (this *AddressSigner) Address() common.Address {
+	return this.address
+}
+
+func (this *AddressSigner) Sign(ctx context.Context, tx *types.MutableTransaction) error {
+	return fmt.Errorf("account %s is not unlocked", this.address.ToBase58())
+}
+
+//ReadToken read an API token from the file at path
+func ReadToken(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("no token file given")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read token file error:%s", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+//encodeTx serialize tx with its signatures to hex
+func encodeTx(tx *types.MutableTransaction) (string, error) {
+	immutable, err := tx.IntoImmutable()
+	if err != nil {
+		return "", fmt.Errorf("tx.IntoImmutable error:%s", err)
+	}
+	return hex.EncodeToString(immutable.ToArray()), nil
+}
+
+func decodeTx(data string) (*types.MutableTransaction, error) {
+	raw, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	tx, err := types.TransactionFromRawBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("types.TransactionFromRawBytes error:%s", err)
+	}
+	mutTx, err := tx.IntoMutable()
+	if err != nil {
+		return nil, fmt.Errorf("tx.IntoMutable error:%s", err)
+	}
+	return mutTx, nil
+}
+
+//sameTx check that signed only differ from tx by its signatures
+func sameTx(tx, signed *types.MutableTransaction) bool {
+	hash, signedHash := tx.Hash(), signed.Hash()
+	return bytes.Equal(hash[:], signedHash[:]) && len(signed.Sigs) >= len(tx.Sigs)
+}
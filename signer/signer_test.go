@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/smartcontract/service/native/header_sync"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAccount(t *testing.T) *sdk.Account {
+	pri, pub, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	assert.Nil(t, err)
+	return &sdk.Account{
+		PrivateKey: pri,
+		PublicKey:  pub,
+		Address:    types.AddressFromPubKey(pub),
+		SigScheme:  s.SHA256withECDSA,
+	}
+}
+
+func newTestTx(t *testing.T) *types.MutableTransaction {
+	tx, err := sdk.NewOntologySdk().Native.NewNativeInvokeTransaction(1, 0, 20000, 0, utils.HeaderSyncContractAddress,
+		header_sync.SYNC_BLOCK_HEADER, []interface{}{&header_sync.SyncBlockHeaderParam{Headers: [][]byte{{1, 2}}}})
+	assert.Nil(t, err)
+	return tx
+}
+
+func assertSignedBy(t *testing.T, tx *types.MutableTransaction, account *sdk.Account) {
+	assert.Equal(t, account.Address, tx.Payer)
+	assert.Equal(t, 1, len(tx.Sigs))
+	assert.Equal(t, []keypair.PublicKey{account.PublicKey}, tx.Sigs[0].PubKeys)
+	txHash := tx.Hash()
+	assert.Nil(t, signature.Verify(account.PublicKey, txHash.ToArray(), tx.Sigs[0].SigData[0]))
+}
+
+func TestAccountSigner(t *testing.T) {
+	account := newTestAccount(t)
+	signer := NewAccountSigner(account)
+	assert.Equal(t, account.Address, signer.Address())
+	tx := newTestTx(t)
+	assert.Nil(t, signer.Sign(context.Background(), tx))
+	assertSignedBy(t, tx, account)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	account := newTestAccount(t)
+	other := newTestAccount(t)
+	server := httptest.NewServer(NewServer([]*ServedAccount{
+		{Account: account, Token: "secret"},
+		{Account: other, Token: "other secret"},
+	}))
+	defer server.Close()
+
+	_, err := NewRemoteSigner(server.URL, "wrong", account.Address.ToBase58())
+	assert.NotNil(t, err)
+	//each token only gives access to its account
+	_, err = NewRemoteSigner(server.URL, "secret", other.Address.ToBase58())
+	assert.NotNil(t, err)
+	_, err = NewRemoteSigner(server.URL, "other secret", account.Address.ToBase58())
+	assert.NotNil(t, err)
+
+	signer, err := NewRemoteSigner(server.URL, "secret", account.Address.ToBase58())
+	assert.Nil(t, err)
+	assert.Equal(t, account.Address, signer.Address())
+	tx := newTestTx(t)
+	assert.Nil(t, signer.Sign(context.Background(), tx))
+	assertSignedBy(t, tx, account)
+
+	//the signer refuses txs paid by another account
+	tx = newTestTx(t)
+	tx.Payer = other.Address
+	assert.NotNil(t, signer.Sign(context.Background(), tx))
+	assert.Equal(t, 0, len(tx.Sigs))
+
+	//and txs which are not relay txs
+	tx, err = sdk.NewOntologySdk().Native.NewNativeInvokeTransaction(1, 0, 20000, 0, utils.OngContractAddress,
+		"transfer", []interface{}{common.ADDRESS_EMPTY})
+	assert.Nil(t, err)
+	err = signer.Sign(context.Background(), tx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Equal(t, 0, len(tx.Sigs))
+}
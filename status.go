@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ontio/crossChainClient/cmd"
+	"github.com/ontio/crossChainClient/common"
+	"github.com/ontio/crossChainClient/config"
+	"github.com/ontio/crossChainClient/service"
+	sdk "github.com/ontio/ontology-go-sdk"
+	"github.com/urfave/cli"
+)
+
+//showStatus print the cross chain sync state of every route read from the chains. It needs neither
+//the wallet password nor the checkpoint store, so it works next to a running relayer
+func showStatus(ctx *cli.Context) error {
+	if !initConfig(ctx) {
+		return fmt.Errorf("load config error")
+	}
+	if err := config.DefConfig.Validate(); err != nil {
+		return fmt.Errorf("invalid config, run config validate for details:%s", err)
+	}
+	blocks := ctx.Uint(cmd.GetFlagName(cmd.StatusBlocksFlag))
+	if blocks > math.MaxUint32 {
+		return fmt.Errorf("invalid --%s %d", cmd.GetFlagName(cmd.StatusBlocksFlag), blocks)
+	}
+	loader := common.NewAccountLoader(sdk.NewOntologySdk(), getPasswordSource(ctx))
+	defer loader.Close()
+	accounts, err := loadAddresses(loader)
+	if err != nil {
+		return fmt.Errorf("loadAddresses error:%s", err)
+	}
+	syncService, err := service.NewSyncService(newClients(), accounts, nil)
+	if err != nil {
+		return fmt.Errorf("service.NewSyncService error:%s", err)
+	}
+	statuses := syncService.SyncStatuses(context.Background(), uint32(blocks))
+
+	if ctx.Bool(cmd.GetFlagName(cmd.JsonFlag)) {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("json.MarshalIndent error:%s", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, status := range statuses {
+			printStatus(status, blocks != 0)
+		}
+	}
+	for _, status := range statuses {
+		if len(status.Errors) != 0 {
+			return fmt.Errorf("the status of some routes is incomplete")
+		}
+	}
+	return nil
+}
+
+//printStatus print status for humans, with the pending requests if the blocks were scanned
+func printStatus(status *service.SyncStatus, scanned bool) {
+	fmt.Printf("route %s\n", status.Name)
+	fmt.Printf("  source height:       %d\n", status.SourceHeight)
+	fmt.Printf("  header sync height:  %d\n", status.SyncHeight)
+	fmt.Printf("  lag:                 %d blocks\n", status.Lag)
+	for _, balance := range status.Balances {
+		fmt.Printf("  %s account %s: %.9f ONG\n", balance.Role, balance.Address,
+			float64(balance.Balance)/service.ONG_DECIMALS)
+	}
+	if scanned {
+		ids := make([]string, 0, len(status.PendingRequests))
+		for _, requestID := range status.PendingRequests {
+			ids = append(ids, fmt.Sprint(requestID))
+		}
+		line := fmt.Sprintf("  pending requests:    %d in blocks %d-%d", len(ids), status.ScanStart, status.SourceHeight)
+		if len(ids) != 0 {
+			line += ": " + strings.Join(ids, ", ")
+		}
+		fmt.Println(line)
+	}
+	for _, err := range status.Errors {
+		fmt.Printf("  error: %s\n", err)
+	}
+}